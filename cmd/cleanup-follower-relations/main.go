@@ -0,0 +1,58 @@
+// Command cleanup-follower-relations removes the follower relations that break the follow graph rules, self follows,
+// relations with missing users and duplicates, and recounts the counters of the users involved. The indexes the
+// duplicates kept from being built at startup are built afterwards.
+//
+//	go run ./cmd/cleanup-follower-relations -dry-run
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/db"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Report the invalid follower relations without deleting them")
+	includeInactive := flag.Bool("include-inactive", false, "Also remove the relations with inactive users")
+	flag.Parse()
+
+	invalidFollowerRelations, err := crud.FindInvalidFollowerRelations(*includeInactive)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reasonsCount := map[string]int{}
+	for _, invalidFollowerRelation := range invalidFollowerRelations {
+		reasonsCount[invalidFollowerRelation.Reason]++
+
+		log.Printf("%s: %s (%s -> %s)",
+			invalidFollowerRelation.Reason,
+			invalidFollowerRelation.FollowerRelation.ID.Hex(),
+			invalidFollowerRelation.FollowerRelation.FollowerID.Hex(),
+			invalidFollowerRelation.FollowerRelation.FollowedID.Hex(),
+		)
+	}
+
+	for reason, count := range reasonsCount {
+		log.Printf("%d %s relations", count, reason)
+	}
+
+	if *dryRun {
+		return
+	}
+
+	if len(invalidFollowerRelations) > 0 {
+		if err := crud.DeleteInvalidFollowerRelations(invalidFollowerRelations); err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("deleted %d follower relations", len(invalidFollowerRelations))
+	}
+
+	// The unique follower relations index can't be built over duplicates
+	if err := db.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+}
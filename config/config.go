@@ -12,13 +12,41 @@ import (
 type config struct {
 	AccessTokenExpirationMinutes        int
 	PasswordResetTokenExpirationMinutes int
+	EmailChangeTokenExpirationMinutes   int
+	DataExportLinkExpirationMinutes     int
+	MediaLinkExpirationMinutes          int
+	MagicLinkExpirationMinutes          int
+	DataExportRetentionDays             int
+	NotificationRetentionDays           int    `env:"NOTIFICATION_RETENTION_DAYS" validate:"min=1"`
+	ConversationMaxParticipants         int    `env:"CONVERSATION_MAX_PARTICIPANTS" validate:"min=2"`
 	ClientUrl                           string `env:"CLIENT_URL" validate:"omitempty,url"`
 	BackendCorsOrigins                  string `env:"BACKEND_CORS_ORIGINS" validate:"required"`
 	ProjectName                         string `env:"PROJECT_NAME"`
 	SecretKey                           string `env:"SECRET_KEY" validate:"required"`
 	UsersOpenRegistration               bool   `env:"USERS_OPEN_REGISTRATION"`
+	UserDeletionGracePeriodDays         int    `env:"USER_DELETION_GRACE_PERIOD_DAYS" validate:"min=0"`
+	MagicLinkEnabled                    bool   `env:"MAGIC_LINK_ENABLED"`
 	FirstSuperuser                      string `env:"FIRST_SUPERUSER" validate:"required,email"`
 	FirstSuperuserPassword              string `env:"FIRST_SUPERUSER_PASSWORD" validate:"required,min=8"`
+	PasswordMinLength                   int    `env:"PASSWORD_MIN_LENGTH" validate:"min=1"`
+	PasswordMinCharacterClasses         int    `env:"PASSWORD_MIN_CHARACTER_CLASSES" validate:"min=0,max=4"`
+	BreachedPasswordsDir                string `env:"BREACHED_PASSWORDS_DIR"`
+	PasswordHashMemory                  int    `env:"PASSWORD_HASH_MEMORY" validate:"min=8192"`
+	PasswordHashIterations              int    `env:"PASSWORD_HASH_ITERATIONS" validate:"min=1"`
+	PasswordHashParallelism             int    `env:"PASSWORD_HASH_PARALLELISM" validate:"min=1,max=255"`
+	EventsBroker                        string `env:"EVENTS_BROKER" validate:"oneof=memory mongo"`
+	EventsBufferSize                    int    `env:"EVENTS_BUFFER_SIZE" validate:"min=1"`
+	EventsReplayMinutes                 int    `env:"EVENTS_REPLAY_MINUTES" validate:"min=1"`
+	PostEditWindowMinutes               int    `env:"POST_EDIT_WINDOW_MINUTES" validate:"min=0"`
+	PostRestoreDays                     int    `env:"POST_RESTORE_DAYS" validate:"min=0"`
+	PostDeletedRetentionDays            int    `env:"POST_DELETED_RETENTION_DAYS" validate:"gtefield=PostRestoreDays"`
+	StorageBackend                      string `env:"STORAGE_BACKEND" validate:"oneof=local"`
+	StorageLocalDir                     string `env:"STORAGE_LOCAL_DIR" validate:"required"`
+	MediaMaxImageSizeMB                 int    `env:"MEDIA_MAX_IMAGE_SIZE_MB" validate:"min=1"`
+	MediaMaxVideoSizeMB                 int    `env:"MEDIA_MAX_VIDEO_SIZE_MB" validate:"min=1"`
+	MediaMaxVideoSeconds                int    `env:"MEDIA_MAX_VIDEO_SECONDS" validate:"min=1"`
+	FfmpegPath                          string `env:"FFMPEG_PATH"`
+	FfprobePath                         string `env:"FFPROBE_PATH"`
 	EmailsEnabled                       bool   `env:"EMAILS_ENABLED"`
 	EmailsApiKey                        string `env:"EMAILS_API_KEY"`
 	DBUser                              string `env:"DB_USER" validate:"required"`
@@ -70,6 +98,29 @@ func getConfig() config {
 		// 60 minutes * 24 hours * 8 days = 8 days
 		AccessTokenExpirationMinutes:        60 * 24 * 8,
 		PasswordResetTokenExpirationMinutes: 15,
+		EmailChangeTokenExpirationMinutes:   60,
+		DataExportLinkExpirationMinutes:     15,
+		MediaLinkExpirationMinutes:          60,
+		MagicLinkExpirationMinutes:          15,
+		DataExportRetentionDays:             7,
+		NotificationRetentionDays:           90,
+		ConversationMaxParticipants:         10,
+		PasswordMinLength:                   8,
+		PasswordMinCharacterClasses:         2,
+		EventsBroker:                        "memory",
+		EventsBufferSize:                    64,
+		EventsReplayMinutes:                 10,
+		PostRestoreDays:                     30,
+		PostDeletedRetentionDays:            90,
+		StorageBackend:                      "local",
+		StorageLocalDir:                     "uploads",
+		MediaMaxImageSizeMB:                 10,
+		MediaMaxVideoSizeMB:                 50,
+		MediaMaxVideoSeconds:                60,
+		// argon2id parameters in KiB, passes and threads
+		PasswordHashMemory:      64 * 1024,
+		PasswordHashIterations:  3,
+		PasswordHashParallelism: 2,
 	}
 
 	err := parseConfig(&conf)
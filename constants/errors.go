@@ -1,18 +1,66 @@
 package constants
 
 const (
-	InternalServerError               = "internal_server_error"
-	EndpointNotFound                  = "endpoint_not_found"
-	InvalidCredentials                = "invalid_credentials"
-	InvalidJwt                        = "invalid_jwt"
-	InsufficientPrivileges            = "insufficient_privileges"
-	CurrentUserNotFound               = "current_user_not_found"
-	CurrentUserInactive               = "current_user_inactive"
-	CurrentUserNotSuperuser           = "current_user_not_superuser"
-	UserAlreadyRegistered             = "user_already_registered"
-	UserNotFound                      = "user_not_found"
-	UserInactive                      = "user_inactive"
-	FollowerRelationAlreadyRegistered = "follower_relation_already_registered"
-	FollowerRelationNotFound          = "follower_relation_not_found"
-	PostNotFound                      = "post_not_found"
+	InternalServerError                 = "internal_server_error"
+	EndpointNotFound                    = "endpoint_not_found"
+	WebSocketUpgradeRequired            = "websocket_upgrade_required"
+	RequestBodyTooLarge                 = "request_body_too_large"
+	InvalidCredentials                  = "invalid_credentials"
+	IncorrectPassword                   = "incorrect_password"
+	InvalidJwt                          = "invalid_jwt"
+	InsufficientPrivileges              = "insufficient_privileges"
+	CurrentUserNotFound                 = "current_user_not_found"
+	CurrentUserInactive                 = "current_user_inactive"
+	CurrentUserNotSuperuser             = "current_user_not_superuser"
+	UserAlreadyRegistered               = "user_already_registered"
+	HandleAlreadyRegistered             = "handle_already_registered"
+	UserNotFound                        = "user_not_found"
+	UserInactive                        = "user_inactive"
+	UserIsCurrentUser                   = "user_is_current_user"
+	UserBlocked                         = "user_blocked"
+	UserPrivate                         = "user_private"
+	BlockAlreadyRegistered              = "block_already_registered"
+	BlockNotFound                       = "block_not_found"
+	MuteAlreadyRegistered               = "mute_already_registered"
+	MuteNotFound                        = "mute_not_found"
+	CannotFollowSelf                    = "cannot_follow_self"
+	FollowerRelationAlreadyRegistered   = "follower_relation_already_registered"
+	FollowerRelationNotFound            = "follower_relation_not_found"
+	PostNotFound                        = "post_not_found"
+	RepostAlreadyRegistered             = "repost_already_registered"
+	RepostNotFound                      = "repost_not_found"
+	RepostNotEditable                   = "repost_not_editable"
+	PostNotEditable                     = "post_not_editable"
+	PostNotRestorable                   = "post_not_restorable"
+	PostAlreadyPublished                = "post_already_published"
+	PostPublishAtInvalid                = "post_publish_at_invalid"
+	PostNotShareable                    = "post_not_shareable"
+	FollowRequestNotFound               = "follow_request_not_found"
+	LikeAlreadyRegistered               = "like_already_registered"
+	LikeNotFound                        = "like_not_found"
+	CommentNotFound                     = "comment_not_found"
+	MediaNotFound                       = "media_not_found"
+	MediaNotAttachable                  = "media_not_attachable"
+	MediaTooLarge                       = "media_too_large"
+	MediaTypeNotSupported               = "media_type_not_supported"
+	MediaInvalid                        = "media_invalid"
+	MediaTooLong                        = "media_too_long"
+	BookmarkAlreadyRegistered           = "bookmark_already_registered"
+	BookmarkNotFound                    = "bookmark_not_found"
+	BookmarkCollectionAlreadyRegistered = "bookmark_collection_already_registered"
+	BookmarkCollectionNotFound          = "bookmark_collection_not_found"
+	NotificationNotFound                = "notification_not_found"
+	ConversationNotFound                = "conversation_not_found"
+	ConversationParticipantsInvalid     = "conversation_participants_invalid"
+	MessageRequestPending               = "message_request_pending"
+	MessageNotFound                     = "message_not_found"
+	MessageNotEditable                  = "message_not_editable"
+	DataExportNotFound                  = "data_export_not_found"
+	DataExportInProgress                = "data_export_in_progress"
+	DataExportNotReady                  = "data_export_not_ready"
+	DataExportExpired                   = "data_export_expired"
+	PasswordTooShort                    = "password_too_short"
+	PasswordTooSimple                   = "password_too_simple"
+	PasswordContainsPersonalInfo        = "password_contains_personal_info"
+	PasswordBreached                    = "password_breached"
 )
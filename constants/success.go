@@ -1,8 +1,21 @@
 package constants
 
 const (
-	EmailSent               = "email_sent"
-	PasswordUpdated         = "password_updated"
-	PostDeleted             = "post_deleted"
-	FollowerRelationDeleted = "follower_relation_deleted"
+	EmailSent                 = "email_sent"
+	PasswordUpdated           = "password_updated"
+	EmailUpdated              = "email_updated"
+	PostDeleted               = "post_deleted"
+	RepostDeleted             = "repost_deleted"
+	UserDeleted               = "user_deleted"
+	UserDeletionScheduled     = "user_deletion_scheduled"
+	UserUnblocked             = "user_unblocked"
+	UserUnmuted               = "user_unmuted"
+	FollowerRelationDeleted   = "follower_relation_deleted"
+	FollowRequestRejected     = "follow_request_rejected"
+	LikeDeleted               = "like_deleted"
+	CommentDeleted            = "comment_deleted"
+	BookmarkDeleted           = "bookmark_deleted"
+	BookmarkCollectionDeleted = "bookmark_collection_deleted"
+	NotificationsRead         = "notifications_read"
+	ConversationLeft          = "conversation_left"
 )
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wilfredohq/fiber-start/config"
@@ -9,6 +10,7 @@ import (
 	"github.com/wilfredohq/fiber-start/crud"
 	"github.com/wilfredohq/fiber-start/models"
 	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -103,12 +105,12 @@ func RecoverAccount(c *fiber.Ctx) error {
 
 type ResetPasswordBody struct {
 	ResetToken  string `json:"token" validate:"required,jwt"`
-	NewPassword string `json:"newPassword" validate:"required,min=8"`
+	NewPassword string `json:"newPassword" validate:"required"`
 } // @Name ResetPassword
 
 // @Tags Account
 // @Summary Reset Password
-// @Description Reset password
+// @Description Reset password and revoke all sessions
 // @Accept json
 // @Produce json
 // @Param body body ResetPasswordBody true "Body"
@@ -146,14 +148,307 @@ func ResetPassword(c *fiber.Ctx) error {
 		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserInactive})
 	}
 
-	userUpdate := models.UserUpdate{
-		Password: &body.NewPassword,
+	passwordErrors, err := utils.PasswordPolicyErrors("newPassword", body.NewPassword, userResponse.Email, userResponse.FullName)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+	if passwordErrors != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: passwordErrors})
 	}
 
-	userResponse, err = crud.UpdateUser(userResponse.ID, userUpdate)
-	if err != nil {
+	// Whoever knew the old password must not keep a session
+	sessionsRevokedAt := time.Now().Truncate(time.Millisecond)
+
+	if _, err := crud.UpdateUserPassword(userResponse.ID, body.NewPassword, sessionsRevokedAt); err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
 	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.PasswordUpdated})
 }
+
+type ChangePasswordBody struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+} // @Name ChangePassword
+
+// @Tags Account
+// @Summary Change Password
+// @Description Change password and revoke the other sessions
+// @Accept json
+// @Produce json
+// @Param body body ChangePasswordBody true "Body"
+// @Success 200 {object} TokenResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/password [post]
+// @Security ApiKeyAuth
+func ChangePassword(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	body := ChangePasswordBody{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.AuthenticateUser(currentUser.Email, body.CurrentPassword); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Error{Detail: constants.IncorrectPassword})
+	}
+
+	passwordErrors, err := utils.PasswordPolicyErrors("newPassword", body.NewPassword, currentUser.Email, currentUser.FullName)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+	if passwordErrors != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: passwordErrors})
+	}
+
+	// Tokens and dates carry millisecond precision, so the new token below stays valid
+	sessionsRevokedAt := time.Now().Truncate(time.Millisecond)
+
+	if _, err := crud.UpdateUserPassword(currentUser.ID, body.NewPassword, sessionsRevokedAt); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	tokenString, err := utils.GetJwt(currentUser.ID.Hex(), config.Config.AccessTokenExpirationMinutes)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(TokenResponse{AccessToken: tokenString, TokenType: "Bearer"})
+}
+
+type ChangeEmailBody struct {
+	NewEmail string `json:"newEmail" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+} // @Name ChangeEmail
+
+// @Tags Account
+// @Summary Change Email
+// @Description Send a confirmation link to the new email and a notice to the current one
+// @Accept json
+// @Produce json
+// @Param body body ChangeEmailBody true "Body"
+// @Success 200 {object} models.Msg
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/email [post]
+// @Security ApiKeyAuth
+func ChangeEmail(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	body := ChangeEmailBody{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.AuthenticateUser(currentUser.Email, body.Password); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.Error{Detail: constants.IncorrectPassword})
+	}
+
+	if _, err := crud.FindOneUserByEmail(body.NewEmail); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.UserAlreadyRegistered})
+	}
+
+	utils.SendEmailChangeConfirmationEmail(currentUser.ID.Hex(), currentUser.Email, body.NewEmail)
+	utils.SendEmailChangeNoticeEmail(currentUser.Email, body.NewEmail)
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.EmailSent})
+}
+
+type ConfirmEmailChangeBody struct {
+	ConfirmationToken string `json:"token" validate:"required,jwt"`
+} // @Name ConfirmEmailChange
+
+// @Tags Account
+// @Summary Confirm Email Change
+// @Description Confirm email change
+// @Accept json
+// @Produce json
+// @Param body body ConfirmEmailChangeBody true "Body"
+// @Success 200 {object} models.Msg
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/email/confirm [post]
+func ConfirmEmailChange(c *fiber.Ctx) error {
+	body := ConfirmEmailChangeBody{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	claims, err := utils.GetEmailChangeJwtClaims(body.ConfirmationToken)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	userResponse, err := crud.FindOneUserById(userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if !userResponse.IsActive {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserInactive})
+	}
+
+	// The link is only valid while the account still has the email it was requested from
+	if userResponse.Email != claims.Email {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	if _, err := crud.FindOneUserByEmail(claims.NewEmail); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.UserAlreadyRegistered})
+	}
+
+	if _, err := crud.UpdateUserEmail(userResponse.ID, claims.NewEmail); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.EmailUpdated})
+}
+
+type RequestMagicLinkBody struct {
+	Email string `json:"email" validate:"required,email"`
+} // @Name RequestMagicLink
+
+// @Tags Account
+// @Summary Request Magic Link
+// @Description Email a single-use sign-in link, the response does not reveal whether the email exists
+// @Accept json
+// @Produce json
+// @Param body body RequestMagicLinkBody true "Body"
+// @Success 200 {object} models.Msg
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/magic-link [post]
+func RequestMagicLink(c *fiber.Ctx) error {
+	body := RequestMagicLinkBody{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	userResponse, err := crud.FindOneUserByEmail(body.Email)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.EmailSent})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if !userResponse.IsActive {
+		return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.EmailSent})
+	}
+
+	token, err := utils.GetRandomToken()
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	magicLinkCreate := models.MagicLinkCreate{
+		UserID:    userResponse.ID,
+		TokenHash: utils.GetTokenHash(token),
+		ExpiresAt: time.Now().Add(time.Minute * time.Duration(config.Config.MagicLinkExpirationMinutes)),
+	}
+
+	if err := crud.InsertMagicLink(magicLinkCreate); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	utils.SendMagicLinkEmail(userResponse.Email, token)
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.EmailSent})
+}
+
+type MagicLinkLoginBody struct {
+	Token string `json:"token" validate:"required,hexadecimal"`
+} // @Name MagicLinkLogin
+
+// @Tags Account
+// @Summary Magic Link Login
+// @Description Exchange a magic link token for an access token
+// @Accept json
+// @Produce json
+// @Param body body MagicLinkLoginBody true "Body"
+// @Success 200 {object} TokenResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/magic-link/login [post]
+func MagicLinkLogin(c *fiber.Ctx) error {
+	body := MagicLinkLoginBody{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	magicLink, err := crud.UseMagicLink(utils.GetTokenHash(body.Token))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidCredentials})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	userResponse, err := crud.FindOneUserById(magicLink.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidCredentials})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if !userResponse.IsActive {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserInactive})
+	}
+
+	tokenString, err := utils.GetJwt(userResponse.ID.Hex(), config.Config.AccessTokenExpirationMinutes)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(TokenResponse{AccessToken: tokenString, TokenType: "Bearer"})
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Account
+// @Summary Get Blocked Users
+// @Description Get the users blocked by the current user
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/account/blocks [get]
+// @Security ApiKeyAuth
+func GetBlockedUsers(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	usersResponse, err := crud.FindAllBlockedUsers(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(usersResponse)
+}
+
+// @Tags Users
+// @Summary Create Block
+// @Description Block a user, which removes the follower relations between both users and hides their content from each other
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 201 {object} models.BlockResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/block [post]
+// @Security ApiKeyAuth
+func CreateBlock(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	if params.UserID == currentUser.ID {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.UserIsCurrentUser})
+	}
+
+	if _, err := crud.FindOneUserById(params.UserID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if _, err := crud.FindOneBlockByUserIds(currentUser.ID, params.UserID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BlockAlreadyRegistered})
+	}
+
+	blockResponse, err := crud.InsertBlock(models.BlockCreate{BlockerID: currentUser.ID, BlockedID: params.UserID})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BlockAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusCreated).JSON(blockResponse)
+}
+
+// @Tags Users
+// @Summary Delete Block
+// @Description Unblock a user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/block [delete]
+// @Security ApiKeyAuth
+func DeleteBlock(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	blockResponse, err := crud.FindOneBlockByUserIds(currentUser.ID, params.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.BlockNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if err := crud.DeleteBlock(blockResponse.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.UserUnblocked})
+}
@@ -0,0 +1,422 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// currentBookmarkCollection returns a bookmark collection of the current user, the collections of other users are
+// reported as missing since bookmarks are private
+func currentBookmarkCollection(bookmarkCollectionID primitive.ObjectID, currentUser models.UserResponse) (models.BookmarkCollectionResponse, *fiber.Error) {
+	bookmarkCollectionResponse, err := crud.FindOneBookmarkCollectionById(bookmarkCollectionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return bookmarkCollectionResponse, fiber.NewError(http.StatusNotFound, constants.BookmarkCollectionNotFound)
+		} else {
+			return bookmarkCollectionResponse, fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if bookmarkCollectionResponse.UserID != currentUser.ID {
+		return models.BookmarkCollectionResponse{}, fiber.NewError(http.StatusNotFound, constants.BookmarkCollectionNotFound)
+	}
+
+	return bookmarkCollectionResponse, nil
+}
+
+// @Tags Bookmarks
+// @Summary Get Bookmarks
+// @Description Get the posts bookmarked by the current user from the newest bookmark backwards, pass nextCursor as cursor to get older bookmarks
+// @Accept json
+// @Produce json
+// @Param collectionId query string false "Bookmark collection id"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {object} models.BookmarksPage
+// @Failure default {object} models.Error
+// @Router /api/v1/account/bookmarks [get]
+// @Security ApiKeyAuth
+func GetBookmarks(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		CollectionID primitive.ObjectID `query:"collectionId"`
+		Cursor       primitive.ObjectID `query:"cursor"`
+		Limit        int                `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if !query.CollectionID.IsZero() {
+		if _, fiberErr := currentBookmarkCollection(query.CollectionID, currentUser); fiberErr != nil {
+			return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+		}
+	}
+
+	bookmarksPage, err := crud.FindAllBookmarkedPosts(currentUser.ID, query.CollectionID, query.Cursor, int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(bookmarksPage)
+}
+
+// @Tags Bookmarks
+// @Summary Create Bookmark
+// @Description Bookmark a post, optionally into one of the current user's collections
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param body body models.BookmarkCreate false "Body"
+// @Success 201 {object} models.BookmarkResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/bookmarks [post]
+// @Security ApiKeyAuth
+func CreateBookmark(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.BookmarkCreate{}
+
+	// The body is optional, bookmarks without a collection don't need one
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+		}
+	}
+
+	body.UserID = currentUser.ID
+	body.PostID = params.PostID
+
+	if _, err := crud.FindOnePostById(params.PostID, currentUser.ID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if body.CollectionID != nil {
+		if _, fiberErr := currentBookmarkCollection(*body.CollectionID, currentUser); fiberErr != nil {
+			return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+		}
+	}
+
+	if _, err := crud.FindOneBookmarkByUserAndPostIds(currentUser.ID, params.PostID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkAlreadyRegistered})
+	}
+
+	bookmarkResponse, err := crud.InsertBookmark(body)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusCreated).JSON(bookmarkResponse)
+}
+
+// @Tags Bookmarks
+// @Summary Update Bookmark
+// @Description Move a bookmark of the current user to another collection, or out of its collection when collectionId is null
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param body body models.BookmarkUpdate true "Body"
+// @Success 200 {object} models.BookmarkResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/bookmarks [patch]
+// @Security ApiKeyAuth
+func UpdateBookmark(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.BookmarkUpdate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	bookmarkResponse, err := crud.FindOneBookmarkByUserAndPostIds(currentUser.ID, params.PostID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.BookmarkNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if body.CollectionID != nil {
+		if _, fiberErr := currentBookmarkCollection(*body.CollectionID, currentUser); fiberErr != nil {
+			return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+		}
+	}
+
+	bookmarkResponse, err = crud.UpdateBookmark(bookmarkResponse.ID, body)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(bookmarkResponse)
+}
+
+// @Tags Bookmarks
+// @Summary Delete Bookmark
+// @Description Remove the current user's bookmark from a post
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/bookmarks [delete]
+// @Security ApiKeyAuth
+func DeleteBookmark(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	bookmarkResponse, err := crud.FindOneBookmarkByUserAndPostIds(currentUser.ID, params.PostID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.BookmarkNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if err := crud.DeleteBookmark(bookmarkResponse.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.BookmarkDeleted})
+}
+
+// @Tags Bookmarks
+// @Summary Get Bookmark Collections
+// @Description Get the bookmark collections of the current user
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.BookmarkCollectionResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/account/bookmark-collections [get]
+// @Security ApiKeyAuth
+func GetBookmarkCollections(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	bookmarkCollectionsResponse, err := crud.FindAllBookmarkCollectionsByUserId(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(bookmarkCollectionsResponse)
+}
+
+// @Tags Bookmarks
+// @Summary Create Bookmark Collection
+// @Description Create a bookmark collection for the current user
+// @Accept json
+// @Produce json
+// @Param body body models.BookmarkCollectionCreate true "Body"
+// @Success 201 {object} models.BookmarkCollectionResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/bookmark-collections [post]
+// @Security ApiKeyAuth
+func CreateBookmarkCollection(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	body := models.BookmarkCollectionCreate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body.UserID = currentUser.ID
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.FindOneBookmarkCollectionByUserIdAndName(currentUser.ID, *body.Name); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkCollectionAlreadyRegistered})
+	}
+
+	bookmarkCollectionResponse, err := crud.InsertBookmarkCollection(body)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkCollectionAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusCreated).JSON(bookmarkCollectionResponse)
+}
+
+// @Tags Bookmarks
+// @Summary Update Bookmark Collection
+// @Description Rename a bookmark collection of the current user
+// @Accept json
+// @Produce json
+// @Param bookmark_collection_id path string true "Bookmark collection id"
+// @Param body body models.BookmarkCollectionUpdate true "Body"
+// @Success 200 {object} models.BookmarkCollectionResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/account/bookmark-collections/{bookmark_collection_id} [patch]
+// @Security ApiKeyAuth
+func UpdateBookmarkCollection(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		BookmarkCollectionID primitive.ObjectID `params:"bookmarkCollectionId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.BookmarkCollectionUpdate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, fiberErr := currentBookmarkCollection(params.BookmarkCollectionID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if body.Name != nil {
+		if bookmarkCollectionResponse, err := crud.FindOneBookmarkCollectionByUserIdAndName(currentUser.ID, *body.Name); err == nil && bookmarkCollectionResponse.ID != params.BookmarkCollectionID {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkCollectionAlreadyRegistered})
+		}
+	}
+
+	bookmarkCollectionResponse, err := crud.UpdateBookmarkCollection(params.BookmarkCollectionID, body)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.BookmarkCollectionAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(bookmarkCollectionResponse)
+}
+
+// @Tags Bookmarks
+// @Summary Delete Bookmark Collection
+// @Description Delete a bookmark collection of the current user, its bookmarks are kept without a collection
+// @Accept json
+// @Produce json
+// @Param bookmark_collection_id path string true "Bookmark collection id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/account/bookmark-collections/{bookmark_collection_id} [delete]
+// @Security ApiKeyAuth
+func DeleteBookmarkCollection(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		BookmarkCollectionID primitive.ObjectID `params:"bookmarkCollectionId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	if _, fiberErr := currentBookmarkCollection(params.BookmarkCollectionID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if err := crud.DeleteBookmarkCollection(params.BookmarkCollectionID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.BookmarkCollectionDeleted})
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/notifications"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Comments
+// @Summary Get Post Comments
+// @Description Get top-level comments of a post, oldest first
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {object} models.CommentsPage
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/comments [get]
+// @Security ApiKeyAuth
+func GetPostComments(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Cursor primitive.ObjectID `query:"cursor"`
+		Limit  int                `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.FindOnePostById(params.PostID, currentUser.ID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	commentsPage, err := crud.FindAllPostComments(params.PostID, query.Cursor, int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(commentsPage)
+}
+
+// @Tags Comments
+// @Summary Create Comment
+// @Description Comment on a post or reply to a comment
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param body body models.CommentCreate true "Body"
+// @Success 201 {object} models.CommentResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/comments [post]
+// @Security ApiKeyAuth
+func CreateComment(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.CommentCreate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body.PostID = params.PostID
+	body.UserID = currentUser.ID
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if body.ParentID != nil {
+		parentResponse, err := crud.FindOneCommentById(*body.ParentID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+			} else {
+				return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+			}
+		}
+
+		if parentResponse.PostID != params.PostID {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+		}
+
+		body.AncestorIDs = append(parentResponse.AncestorIDs, parentResponse.ID)
+	}
+
+	commentResponse, err := crud.InsertComment(body)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	notifications.Notify(models.NotificationCreate{
+		UserID:  postResponse.UserID,
+		Type:    models.NotificationTypeCommented,
+		PostID:  postResponse.ID,
+		ActorID: currentUser.ID,
+	})
+
+	return c.Status(http.StatusCreated).JSON(commentResponse)
+}
+
+// @Tags Comments
+// @Summary Get Comment
+// @Description Get comment
+// @Accept json
+// @Produce json
+// @Param comment_id path string true "Comment id"
+// @Success 200 {object} models.CommentResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/comments/{comment_id} [get]
+// @Security ApiKeyAuth
+func GetComment(c *fiber.Ctx) error {
+	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		CommentID primitive.ObjectID `params:"commentId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	commentResponse, err := crud.FindOneCommentById(params.CommentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(commentResponse)
+}
+
+// @Tags Comments
+// @Summary Get Comment Replies
+// @Description Get direct replies to a comment, oldest first
+// @Accept json
+// @Produce json
+// @Param comment_id path string true "Comment id"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {object} models.CommentsPage
+// @Failure default {object} models.Error
+// @Router /api/v1/comments/{comment_id}/replies [get]
+// @Security ApiKeyAuth
+func GetCommentReplies(c *fiber.Ctx) error {
+	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		CommentID primitive.ObjectID `params:"commentId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Cursor primitive.ObjectID `query:"cursor"`
+		Limit  int                `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.FindOneCommentById(params.CommentID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	commentsPage, err := crud.FindAllCommentReplies(params.CommentID, query.Cursor, int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(commentsPage)
+}
+
+// @Tags Comments
+// @Summary Delete Comment
+// @Description Delete comment with all of its replies
+// @Accept json
+// @Produce json
+// @Param comment_id path string true "Comment id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/comments/{comment_id} [delete]
+// @Security ApiKeyAuth
+func DeleteComment(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		CommentID primitive.ObjectID `params:"commentId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	commentResponse, err := crud.FindOneCommentById(params.CommentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if commentResponse.UserID != currentUser.ID && !currentUser.IsSuperuser {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	if err := crud.DeleteComment(params.CommentID, commentResponse); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.CommentDeleted})
+}
+
+// @Tags Comments
+// @Summary Update Comment
+// @Description Update comment
+// @Accept json
+// @Produce json
+// @Param comment_id path string true "Comment id"
+// @Param body body models.CommentUpdate true "Body"
+// @Success 200 {object} models.CommentResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/comments/{comment_id} [patch]
+// @Security ApiKeyAuth
+func UpdateComment(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		CommentID primitive.ObjectID `params:"commentId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	commentResponse, err := crud.FindOneCommentById(params.CommentID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.CommentNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if commentResponse.UserID != currentUser.ID && !currentUser.IsSuperuser {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	body := models.CommentUpdate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	commentResponse, err = crud.UpdateComment(params.CommentID, body)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(commentResponse)
+}
@@ -0,0 +1,411 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func findConversationParticipant(conversationResponse models.ConversationResponse, userID primitive.ObjectID) *models.ConversationParticipant {
+	for i, participant := range conversationResponse.Participants {
+		if participant.UserID == userID {
+			return &conversationResponse.Participants[i]
+		}
+	}
+
+	return nil
+}
+
+func conversationParticipantIDs(conversationResponse models.ConversationResponse) []primitive.ObjectID {
+	userIDs := []primitive.ObjectID{}
+	for _, participant := range conversationResponse.Participants {
+		userIDs = append(userIDs, participant.UserID)
+	}
+
+	return userIDs
+}
+
+// currentConversation returns a conversation of the current user, conversations of others are reported as missing
+func currentConversation(conversationID primitive.ObjectID, currentUser models.UserResponse) (models.ConversationResponse, *fiber.Error) {
+	conversationResponse, err := crud.FindOneConversationById(conversationID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return conversationResponse, fiber.NewError(http.StatusNotFound, constants.ConversationNotFound)
+		} else {
+			return conversationResponse, fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if findConversationParticipant(conversationResponse, currentUser.ID) == nil {
+		return conversationResponse, fiber.NewError(http.StatusNotFound, constants.ConversationNotFound)
+	}
+
+	return conversationResponse, nil
+}
+
+func followEachOther(userID primitive.ObjectID, otherUserID primitive.ObjectID) (bool, error) {
+	for _, followerRelationUserIDs := range [][2]primitive.ObjectID{{userID, otherUserID}, {otherUserID, userID}} {
+		followerRelationResponse, err := crud.FindOneFollowerRelationByUserIds(followerRelationUserIDs[0], followerRelationUserIDs[1])
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return false, nil
+			}
+			return false, err
+		}
+
+		if followerRelationResponse.Status == models.FollowerRelationStatusPending {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// @Tags Conversations
+// @Summary Get Conversations
+// @Description Get the conversations of the current user, or the message requests they have not accepted yet
+// @Accept json
+// @Produce json
+// @Param requests query bool false "Message requests"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.ConversationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations [get]
+// @Security ApiKeyAuth
+func GetConversations(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Requests bool `query:"requests"`
+		Skip     int  `query:"skip"`
+		Limit    int  `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	status := models.ParticipantStatusAccepted
+	if query.Requests {
+		status = models.ParticipantStatusPending
+	}
+
+	conversationsResponse, err := crud.FindAllConversationsByUserId(currentUser.ID, status, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(conversationsResponse)
+}
+
+// @Tags Conversations
+// @Summary Create Conversation
+// @Description Start a conversation, one participant makes a one-to-one conversation and several a group.
+// @Description Participants that do not follow each other with the current user receive a message request.
+// @Accept json
+// @Produce json
+// @Param body body models.ConversationCreate true "Body"
+// @Success 200 {object} models.ConversationResponse "Existing one-to-one conversation"
+// @Success 201 {object} models.ConversationResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations [post]
+// @Security ApiKeyAuth
+func CreateConversation(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	body := models.ConversationCreate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body.CreatorID = currentUser.ID
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	participantIDs := []primitive.ObjectID{}
+	for _, participantID := range body.ParticipantIDs {
+		if participantID != currentUser.ID {
+			participantIDs = append(participantIDs, participantID)
+		}
+	}
+
+	if len(participantIDs) == 0 || len(participantIDs)+1 > config.Config.ConversationMaxParticipants {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.ConversationParticipantsInvalid})
+	}
+
+	body.IsGroup = len(participantIDs) > 1
+	body.DirectKey = ""
+	if !body.IsGroup {
+		body.Title = nil
+		body.DirectKey = crud.GetDirectConversationKey(currentUser.ID, participantIDs[0])
+
+		if conversationResponse, err := crud.FindOneDirectConversation(body.DirectKey, currentUser.ID); err == nil {
+			return c.Status(http.StatusOK).JSON(conversationResponse)
+		}
+	}
+
+	body.Participants = []models.ConversationParticipant{
+		{UserID: currentUser.ID, Status: models.ParticipantStatusAccepted},
+	}
+
+	for _, participantID := range participantIDs {
+		userResponse, err := crud.FindOneUserById(participantID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+			} else {
+				return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+			}
+		}
+
+		if !userResponse.IsActive {
+			return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserInactive})
+		}
+
+		if _, err := crud.FindOneBlockBetweenUsers(currentUser.ID, participantID); err == nil {
+			return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserBlocked})
+		}
+
+		mutual, err := followEachOther(currentUser.ID, participantID)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+
+		status := models.ParticipantStatusPending
+		if mutual {
+			status = models.ParticipantStatusAccepted
+		}
+
+		body.Participants = append(body.Participants, models.ConversationParticipant{UserID: participantID, Status: status})
+	}
+
+	conversationResponse, err := crud.InsertConversation(body)
+	if err != nil {
+		// Both users started the same one-to-one conversation at once
+		if mongo.IsDuplicateKeyError(err) {
+			if conversationResponse, err := crud.FindOneDirectConversation(body.DirectKey, currentUser.ID); err == nil {
+				return c.Status(http.StatusOK).JSON(conversationResponse)
+			}
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	events.Publish(participantIDs, models.EventTypeConversationCreated, conversationResponse)
+
+	return c.Status(http.StatusCreated).JSON(conversationResponse)
+}
+
+// @Tags Conversations
+// @Summary Get Conversation
+// @Description Get conversation
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Success 200 {object} models.ConversationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id} [get]
+// @Security ApiKeyAuth
+func GetConversation(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	conversationResponse, fiberErr := currentConversation(params.ConversationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	return c.Status(http.StatusOK).JSON(conversationResponse)
+}
+
+// @Tags Conversations
+// @Summary Accept Conversation
+// @Description Accept a message request
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Success 200 {object} models.ConversationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/accept [post]
+// @Security ApiKeyAuth
+func AcceptConversation(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	conversationResponse, fiberErr := currentConversation(params.ConversationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if findConversationParticipant(conversationResponse, currentUser.ID).Status == models.ParticipantStatusAccepted {
+		return c.Status(http.StatusOK).JSON(conversationResponse)
+	}
+
+	conversationResponse, err := crud.AcceptConversation(params.ConversationID, currentUser.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(conversationResponse)
+}
+
+// @Tags Conversations
+// @Summary Leave Conversation
+// @Description Leave a conversation or decline a message request, one-to-one conversations are deleted
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/leave [post]
+// @Security ApiKeyAuth
+func LeaveConversation(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	if _, fiberErr := currentConversation(params.ConversationID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if err := crud.LeaveConversation(params.ConversationID, currentUser.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.ConversationLeft})
+}
+
+// @Tags Conversations
+// @Summary Read Conversation
+// @Description Mark a conversation as read up to a message, the latest one by default
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Param body body models.ConversationRead false "Body"
+// @Success 200 {object} models.ConversationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/read [post]
+// @Security ApiKeyAuth
+func ReadConversation(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.ConversationRead{}
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+		}
+	}
+
+	conversationResponse, fiberErr := currentConversation(params.ConversationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	var messageResponse models.MessageResponse
+	var err error
+
+	if body.MessageID != nil {
+		messageResponse, err = crud.FindOneMessageById(*body.MessageID)
+	} else {
+		messageResponse, err = crud.FindOneLastMessageByConversationId(params.ConversationID)
+	}
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// Nothing to read in an empty conversation
+			if body.MessageID == nil {
+				return c.Status(http.StatusOK).JSON(conversationResponse)
+			}
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.MessageNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if messageResponse.ConversationID != params.ConversationID {
+		return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.MessageNotFound})
+	}
+
+	conversationResponse, err = crud.ReadConversation(params.ConversationID, currentUser.ID, messageResponse.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	participant := findConversationParticipant(conversationResponse, currentUser.ID)
+	if participant != nil {
+		events.Publish(conversationParticipantIDs(conversationResponse), models.EventTypeConversationRead, models.ConversationReceipt{
+			ConversationID:    conversationResponse.ID,
+			UserID:            participant.UserID,
+			LastReadMessageID: participant.LastReadMessageID,
+			LastReadAt:        participant.LastReadAt,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(conversationResponse)
+}
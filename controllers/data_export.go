@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/jobs"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func withDataExportDownloadUrl(c *fiber.Ctx, dataExportResponse models.DataExportResponse) (models.DataExportResponse, error) {
+	if dataExportResponse.Status != models.DataExportStatusCompleted || dataExportResponse.ExpiresAt.Before(time.Now()) {
+		return dataExportResponse, nil
+	}
+
+	tokenString, err := utils.GetAudienceJwt(dataExportResponse.ID.Hex(), utils.DataExportAudience, config.Config.DataExportLinkExpirationMinutes)
+	if err != nil {
+		return dataExportResponse, err
+	}
+
+	dataExportResponse.DownloadUrl = fmt.Sprintf("%s/api/v1/account/export/%s/download?token=%s", c.BaseURL(), dataExportResponse.ID.Hex(), tokenString)
+
+	return dataExportResponse, nil
+}
+
+// @Tags Account
+// @Summary Create Data Export
+// @Description Start building an archive with the current account data
+// @Accept json
+// @Produce json
+// @Success 202 {object} models.DataExportResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/account/export [post]
+// @Security ApiKeyAuth
+func CreateDataExport(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if _, err := crud.FindOneUnfinishedDataExportByUserId(currentUser.ID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.DataExportInProgress})
+	}
+
+	dataExportResponse, err := crud.InsertDataExport(models.DataExportCreate{UserID: currentUser.ID})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	go jobs.BuildDataExport(dataExportResponse.ID, currentUser.ID)
+
+	return c.Status(http.StatusAccepted).JSON(dataExportResponse)
+}
+
+// @Tags Account
+// @Summary Get Data Export
+// @Description Get data export status and, once completed, a time-limited download link
+// @Accept json
+// @Produce json
+// @Param data_export_id path string true "Data export id"
+// @Success 200 {object} models.DataExportResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/account/export/{data_export_id} [get]
+// @Security ApiKeyAuth
+func GetDataExport(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		DataExportID primitive.ObjectID `params:"dataExportId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	dataExportResponse, err := crud.FindOneDataExportById(params.DataExportID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.DataExportNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if dataExportResponse.UserID != currentUser.ID {
+		return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.DataExportNotFound})
+	}
+
+	dataExportResponse, err = withDataExportDownloadUrl(c, dataExportResponse)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(dataExportResponse)
+}
+
+// @Tags Account
+// @Summary Download Data Export
+// @Description Download data export archive
+// @Produce application/zip
+// @Param data_export_id path string true "Data export id"
+// @Param token query string true "Download token"
+// @Success 200 {file} file
+// @Failure default {object} models.Error
+// @Router /api/v1/account/export/{data_export_id}/download [get]
+func DownloadDataExport(c *fiber.Ctx) error {
+	params := struct {
+		DataExportID primitive.ObjectID `params:"dataExportId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	claims, err := utils.GetAudienceJwtClaims(c.Query("token"), utils.DataExportAudience)
+	if err != nil || claims.Subject != params.DataExportID.Hex() {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	dataExportResponse, err := crud.FindOneDataExportById(params.DataExportID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.DataExportNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if dataExportResponse.Status != models.DataExportStatusCompleted {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.DataExportNotReady})
+	}
+
+	if dataExportResponse.ExpiresAt.Before(time.Now()) {
+		return c.Status(http.StatusGone).JSON(models.Error{Detail: constants.DataExportExpired})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="export-%s.zip"`, dataExportResponse.ID.Hex()))
+
+	if err := crud.DownloadDataExportFile(dataExportResponse.FileID, c); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return nil
+}
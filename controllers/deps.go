@@ -2,11 +2,14 @@ package controllers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/wilfredohq/fiber-start/constants"
 	"github.com/wilfredohq/fiber-start/crud"
 	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -23,9 +26,23 @@ func currentUser(c *fiber.Ctx) (models.UserResponse, *fiber.Error) {
 		}
 	}
 
+	if isSessionRevoked(userResponse, utils.GetLocalJwtClaims(c)) {
+		return userResponse, fiber.NewError(http.StatusUnauthorized, constants.InvalidJwt)
+	}
+
 	return userResponse, nil
 }
 
+// isSessionRevoked reports whether the token was issued before the sessions of its user were revoked
+func isSessionRevoked(userResponse models.UserResponse, claims *jwt.RegisteredClaims) bool {
+	if userResponse.SessionsRevokedAt.IsZero() {
+		return false
+	}
+
+	// Issue times are read back from floats and may lose their last millisecond
+	return claims.IssuedAt == nil || claims.IssuedAt.Add(time.Millisecond).Before(userResponse.SessionsRevokedAt)
+}
+
 func currentActiveUser(c *fiber.Ctx) (models.UserResponse, *fiber.Error) {
 	userResponse, err := currentUser(c)
 	if err != nil {
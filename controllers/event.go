@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/valyala/fasthttp"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	eventsPingInterval            = 15 * time.Second
+	eventsAuthInterval            = 30 * time.Second
+	eventsWriteTimeout            = 10 * time.Second
+	eventsTicketExpirationMinutes = 1
+)
+
+type EventsTicketResponse struct {
+	Ticket    string    `json:"ticket" validate:"required"`
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+} // @Name EventsTicket
+
+// streamAuthorization returns a check of whether a stream may go on. A stream ends with the session it was opened
+// from, when that session expires or is revoked, or when its user is deactivated.
+func streamAuthorization(c *fiber.Ctx) func() bool {
+	userID := c.Locals("userId").(primitive.ObjectID)
+	claims := utils.GetLocalJwtClaims(c)
+
+	// Tickets expire as soon as the stream is open, the session they come from lives as long as an access token
+	expiresAt := time.Time{}
+	if claims.IssuedAt != nil {
+		expiresAt = claims.IssuedAt.Add(time.Duration(config.Config.AccessTokenExpirationMinutes) * time.Minute)
+	}
+	if claims.ExpiresAt != nil && len(claims.Audience) == 0 {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	return func() bool {
+		if expiresAt.IsZero() || time.Now().After(expiresAt) {
+			return false
+		}
+
+		userResponse, err := crud.FindOneUserById(userID)
+		if err != nil {
+			return false
+		}
+
+		return userResponse.IsActive && !isSessionRevoked(userResponse, claims)
+	}
+}
+
+// streamEvents writes the backlog and then the live events of a subscription until it is closed,
+// dropped, a write fails or the stream is no longer authorized. Live events already sent as part
+// of the backlog are skipped.
+func streamEvents(subscription *events.Subscription, backlog []models.Event, write func(models.Event) error, ping func() error, authorized func() bool) {
+	defer subscription.Close()
+
+	sentEventIDs := map[string]bool{}
+
+	for _, event := range backlog {
+		if err := write(event); err != nil {
+			return
+		}
+		sentEventIDs[event.ID] = true
+	}
+
+	ticker := time.NewTicker(eventsPingInterval)
+	defer ticker.Stop()
+
+	authTicker := time.NewTicker(eventsAuthInterval)
+	defer authTicker.Stop()
+
+	for {
+		select {
+		case <-subscription.Done():
+			return
+		case event := <-subscription.Events():
+			if sentEventIDs[event.ID] {
+				continue
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := ping(); err != nil {
+				return
+			}
+		case <-authTicker.C:
+			if !authorized() {
+				return
+			}
+		}
+	}
+}
+
+func lastEventID(c *fiber.Ctx) string {
+	if id := c.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+
+	return c.Query("lastEventId")
+}
+
+// @Tags Events
+// @Summary Get Events
+// @Description Stream the events of the current user as Server-Sent Events. Browsers can send a ticket from POST /api/v1/events/ticket in the ticket query parameter instead of the access token.
+// @Description The stream ends when the session expires or is revoked.
+// @Description Reconnecting with Last-Event-ID (or lastEventId) resumes the stream, a reset event means some events were lost.
+// @Accept json
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "Last event id"
+// @Param lastEventId query string false "Last event id"
+// @Param ticket query string false "Events ticket"
+// @Success 200 {object} models.Event
+// @Failure default {object} models.Error
+// @Router /api/v1/events [get]
+// @Security ApiKeyAuth
+func GetEvents(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	subscription, backlog, err := events.DefaultBroker.Subscribe(currentUser.ID, lastEventID(c))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	authorized := streamAuthorization(c)
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		write := func(event models.Event) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			if event.ID != "" {
+				fmt.Fprintf(w, "id: %s\n", event.ID)
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+
+			return w.Flush()
+		}
+
+		ping := func() error {
+			fmt.Fprint(w, ": ping\n\n")
+
+			return w.Flush()
+		}
+
+		streamEvents(subscription, backlog, write, ping, authorized)
+	}))
+
+	return nil
+}
+
+// @Tags Events
+// @Summary Get Events WebSocket
+// @Description Stream the events of the current user over a WebSocket as JSON messages. Browsers can send a ticket from POST /api/v1/events/ticket in the ticket query parameter instead of the access token.
+// @Description The stream ends when the session expires or is revoked.
+// @Description Reconnecting with lastEventId resumes the stream, a reset event means some events were lost.
+// @Accept json
+// @Produce json
+// @Param lastEventId query string false "Last event id"
+// @Param ticket query string false "Events ticket"
+// @Success 101 {object} models.Event
+// @Failure default {object} models.Error
+// @Router /api/v1/events/ws [get]
+// @Security ApiKeyAuth
+func UpgradeEventsWebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(http.StatusUpgradeRequired).JSON(models.Error{Detail: constants.WebSocketUpgradeRequired})
+	}
+
+	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	c.Locals("lastEventId", lastEventID(c))
+	c.Locals("authorized", streamAuthorization(c))
+
+	return c.Next()
+}
+
+func EventsWebSocket(conn *websocket.Conn) {
+	userID := conn.Locals("userId").(primitive.ObjectID)
+
+	subscription, backlog, err := events.DefaultBroker.Subscribe(userID, conn.Locals("lastEventId").(string))
+	if err != nil {
+		message := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, constants.InternalServerError)
+		conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(eventsWriteTimeout))
+		return
+	}
+
+	// Incoming messages are ignored, reading only detects when the client goes away
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				subscription.Close()
+				return
+			}
+		}
+	}()
+
+	write := func(event models.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+
+		return conn.WriteJSON(event)
+	}
+
+	ping := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventsWriteTimeout))
+	}
+
+	streamEvents(subscription, backlog, write, ping, conn.Locals("authorized").(func() bool))
+}
+
+// @Tags Events
+// @Summary Create Events Ticket
+// @Description Get a short-lived ticket to open an event stream from a browser, where the access token can't be sent in a header
+// @Accept json
+// @Produce json
+// @Success 201 {object} EventsTicketResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/events/ticket [post]
+// @Security ApiKeyAuth
+func CreateEventsTicket(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	claims := utils.GetLocalJwtClaims(c)
+	if claims.IssuedAt == nil {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	ticket, err := utils.GetEventsTicketJwt(currentUser.ID.Hex(), claims.IssuedAt.Time, eventsTicketExpirationMinutes)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	expiresAt := time.Now().Add(eventsTicketExpirationMinutes * time.Minute)
+
+	return c.Status(http.StatusCreated).JSON(EventsTicketResponse{Ticket: ticket, ExpiresAt: expiresAt})
+}
@@ -1,20 +1,40 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wilfredohq/fiber-start/constants"
 	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
 	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/notifications"
 	"github.com/wilfredohq/fiber-start/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// followGraphError maps the errors of the follow graph rules to their responses
+func followGraphError(err error) *fiber.Error {
+	switch {
+	case errors.Is(err, crud.ErrCannotFollowSelf):
+		return fiber.NewError(http.StatusUnprocessableEntity, constants.CannotFollowSelf)
+	case errors.Is(err, crud.ErrFollowUserNotFound):
+		return fiber.NewError(http.StatusNotFound, constants.UserNotFound)
+	case errors.Is(err, crud.ErrFollowUserInactive):
+		return fiber.NewError(http.StatusForbidden, constants.UserInactive)
+	case mongo.IsDuplicateKeyError(err):
+		// The relation was created since it was checked
+		return fiber.NewError(http.StatusConflict, constants.FollowerRelationAlreadyRegistered)
+	default:
+		return fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+	}
+}
+
 // @Tags Follower relations
 // @Summary Create Follower Relation
-// @Description Create follower relation
+// @Description Create follower relation, pending until accepted when the followed user has a private account
 // @Accept json
 // @Produce json
 // @Param body body models.FollowerRelationCreate true "Body"
@@ -29,30 +49,51 @@ func CreateFollowerRelation(c *fiber.Ctx) error {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
-	body := models.FollowerRelationCreate{
-		FollowerID: currentUser.ID,
-	}
+	body := models.FollowerRelationCreate{}
 
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
+	body.FollowerID = currentUser.ID
+
 	validate := utils.NewValidator()
 	if err := validate.Struct(&body); err != nil {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
+	if _, err := crud.FindOneBlockBetweenUsers(currentUser.ID, *body.FollowedID); err == nil {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserBlocked})
+	}
+
 	if _, err := crud.FindOneFollowerRelationByUserIds(currentUser.ID, *body.FollowedID); err == nil {
 		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.FollowerRelationAlreadyRegistered})
 	}
 
 	followerRelationResponse, err := crud.InsertFollowerRelation(body)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		fiberErr := followGraphError(err)
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
 	followerRelationResponse.HasData = true
 
+	if followerRelationResponse.Status == models.FollowerRelationStatusPending {
+		notifications.Notify(models.NotificationCreate{
+			UserID:  followerRelationResponse.FollowedID,
+			Type:    models.NotificationTypeFollowRequested,
+			ActorID: currentUser.ID,
+		})
+	} else {
+		notifications.Notify(models.NotificationCreate{
+			UserID:  followerRelationResponse.FollowedID,
+			Type:    models.NotificationTypeFollowed,
+			ActorID: currentUser.ID,
+		})
+
+		events.Publish([]primitive.ObjectID{followerRelationResponse.FollowedID}, models.EventTypeFollowerCreated, followerRelationResponse)
+	}
+
 	return c.Status(http.StatusCreated).JSON(followerRelationResponse)
 }
 
@@ -135,5 +176,351 @@ func DeleteFollowerRelation(c *fiber.Ctx) error {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
+	if followerRelationResponse.Status == models.FollowerRelationStatusPending {
+		return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.FollowerRelationDeleted})
+	}
+
+	events.Publish([]primitive.ObjectID{followerRelationResponse.FollowedID}, models.EventTypeFollowerDeleted, followerRelationResponse)
+
 	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.FollowerRelationDeleted})
 }
+
+// currentFollowRequest returns a pending follower relation addressed to the current user
+func currentFollowRequest(followerRelationID primitive.ObjectID, currentUser models.UserResponse) (models.FollowerRelationResponse, *fiber.Error) {
+	followerRelationResponse, err := crud.FindOneFollowerRelationById(followerRelationID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.FollowerRelationResponse{}, fiber.NewError(http.StatusNotFound, constants.FollowRequestNotFound)
+		} else {
+			return models.FollowerRelationResponse{}, fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if followerRelationResponse.FollowedID != currentUser.ID || followerRelationResponse.Status != models.FollowerRelationStatusPending {
+		return models.FollowerRelationResponse{}, fiber.NewError(http.StatusNotFound, constants.FollowRequestNotFound)
+	}
+
+	return followerRelationResponse, nil
+}
+
+// @Tags Follower relations
+// @Summary Get Follow Requests
+// @Description Get the pending follow requests of the current user
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.FollowRequestResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/follower-relations/requests [get]
+// @Security ApiKeyAuth
+func GetFollowRequests(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	followRequestsResponse, err := crud.FindAllFollowRequests(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(followRequestsResponse)
+}
+
+// @Tags Follower relations
+// @Summary Accept Follow Request
+// @Description Accept a follow request addressed to the current user
+// @Accept json
+// @Produce json
+// @Param follower_relation_id path string true "Follower relation id"
+// @Success 200 {object} models.FollowerRelationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/follower-relations/{follower_relation_id}/accept [post]
+// @Security ApiKeyAuth
+func AcceptFollowRequest(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		FollowerRelationID primitive.ObjectID `params:"followerRelationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	followerRelationResponse, fiberErr := currentFollowRequest(params.FollowerRelationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	followerRelationResponse, err := crud.AcceptFollowerRelation(params.FollowerRelationID, followerRelationResponse)
+	if err != nil {
+		fiberErr := followGraphError(err)
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	followerRelationResponse.HasData = true
+
+	notifications.Notify(models.NotificationCreate{
+		UserID:  followerRelationResponse.FollowerID,
+		Type:    models.NotificationTypeFollowAccepted,
+		ActorID: currentUser.ID,
+	})
+
+	events.Publish([]primitive.ObjectID{followerRelationResponse.FollowedID}, models.EventTypeFollowerCreated, followerRelationResponse)
+
+	return c.Status(http.StatusOK).JSON(followerRelationResponse)
+}
+
+// @Tags Follower relations
+// @Summary Reject Follow Request
+// @Description Reject a follow request addressed to the current user
+// @Accept json
+// @Produce json
+// @Param follower_relation_id path string true "Follower relation id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/follower-relations/{follower_relation_id}/reject [post]
+// @Security ApiKeyAuth
+func RejectFollowRequest(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		FollowerRelationID primitive.ObjectID `params:"followerRelationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	followerRelationResponse, fiberErr := currentFollowRequest(params.FollowerRelationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if err := crud.DeleteFollowerRelation(params.FollowerRelationID, followerRelationResponse); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.FollowRequestRejected})
+}
+
+// checkFollowListAccess hides the follower lists of private accounts from the users that don't follow them
+func checkFollowListAccess(userID primitive.ObjectID, currentUser models.UserResponse) *fiber.Error {
+	userResponse, err := crud.FindOneUserById(userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fiber.NewError(http.StatusNotFound, constants.UserNotFound)
+		} else {
+			return fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if _, err := crud.FindOneBlockBetweenUsers(currentUser.ID, userResponse.ID); err == nil {
+		return fiber.NewError(http.StatusForbidden, constants.UserBlocked)
+	}
+
+	if !userResponse.IsPrivate || userResponse.ID == currentUser.ID || currentUser.IsSuperuser {
+		return nil
+	}
+
+	followerRelationResponse, err := crud.FindOneFollowerRelationByUserIds(currentUser.ID, userResponse.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fiber.NewError(http.StatusForbidden, constants.UserPrivate)
+		} else {
+			return fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if followerRelationResponse.Status == models.FollowerRelationStatusPending {
+		return fiber.NewError(http.StatusForbidden, constants.UserPrivate)
+	}
+
+	return nil
+}
+
+// @Tags Users
+// @Summary Get Followers
+// @Description Get the followers of a user, annotated with their relation to the current user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.FollowUserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/followers [get]
+// @Security ApiKeyAuth
+func GetFollowers(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if fiberErr := checkFollowListAccess(params.UserID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	followUsersResponse, err := crud.FindAllFollowers(currentUser.ID, params.UserID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(followUsersResponse)
+}
+
+// @Tags Users
+// @Summary Get Following
+// @Description Get the users followed by a user, annotated with their relation to the current user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.FollowUserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/following [get]
+// @Security ApiKeyAuth
+func GetFollowing(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if fiberErr := checkFollowListAccess(params.UserID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	followUsersResponse, err := crud.FindAllFollowing(currentUser.ID, params.UserID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(followUsersResponse)
+}
+
+// @Tags Users
+// @Summary Get Mutuals
+// @Description Get the users that follow a user and are followed back by them
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.FollowUserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/mutuals [get]
+// @Security ApiKeyAuth
+func GetMutuals(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if fiberErr := checkFollowListAccess(params.UserID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	followUsersResponse, err := crud.FindAllMutuals(currentUser.ID, params.UserID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(followUsersResponse)
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/notifications"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Likes
+// @Summary Get Post Likers
+// @Description Get users who liked a post
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/likes [get]
+// @Security ApiKeyAuth
+func GetPostLikers(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.FindOnePostById(params.PostID, currentUser.ID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	usersResponse, err := crud.FindAllLikersByPostId(params.PostID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(usersResponse)
+}
+
+// @Tags Likes
+// @Summary Create Like
+// @Description Like a post
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 201 {object} models.LikeResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/likes [post]
+// @Security ApiKeyAuth
+func CreateLike(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if _, err := crud.FindOneLikeByUserAndPostIds(currentUser.ID, params.PostID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.LikeAlreadyRegistered})
+	}
+
+	likeResponse, err := crud.InsertLike(models.LikeCreate{UserID: currentUser.ID, PostID: params.PostID})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.LikeAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	notifications.Notify(models.NotificationCreate{
+		UserID:  postResponse.UserID,
+		Type:    models.NotificationTypeLiked,
+		PostID:  postResponse.ID,
+		ActorID: currentUser.ID,
+	})
+
+	return c.Status(http.StatusCreated).JSON(likeResponse)
+}
+
+// @Tags Likes
+// @Summary Delete Like
+// @Description Remove the current user's like from a post
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/likes [delete]
+// @Security ApiKeyAuth
+func DeleteLike(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	likeResponse, err := crud.FindOneLikeByUserAndPostIds(currentUser.ID, params.PostID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.LikeNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if err := crud.DeleteLike(likeResponse.ID, likeResponse); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.LikeDeleted})
+}
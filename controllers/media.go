@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/storage"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mediaError maps the errors of the media processing to their response
+func mediaError(err error) *fiber.Error {
+	switch {
+	case errors.Is(err, utils.ErrMediaTypeNotSupported):
+		return fiber.NewError(http.StatusUnsupportedMediaType, constants.MediaTypeNotSupported)
+	case errors.Is(err, utils.ErrMediaInvalid):
+		return fiber.NewError(http.StatusUnprocessableEntity, constants.MediaInvalid)
+	case errors.Is(err, utils.ErrMediaTooLong):
+		return fiber.NewError(http.StatusUnprocessableEntity, constants.MediaTooLong)
+	default:
+		return fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+	}
+}
+
+// uploadedFile reads the "file" field of a multipart body
+func uploadedFile(c *fiber.Ctx) ([]byte, error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// @Tags Media
+// @Summary Create Media
+// @Description Upload an image (JPEG, PNG, GIF or WebP) or a short video (MP4 or WebM) to attach to a post. Metadata is stripped and a thumbnail is generated. Media not attached to a post within a day is deleted.
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "File"
+// @Success 201 {object} models.MediaResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/media [post]
+// @Security ApiKeyAuth
+func CreateMedia(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	content, err := uploadedFile(c)
+	if err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	// The type comes from the content, the one declared by the client is ignored
+	contentType := utils.SniffMediaType(content)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var mediaType string
+	var processedMedia utils.ProcessedMedia
+
+	switch {
+	case utils.IsImageMediaType(contentType):
+		if len(content) > config.Config.MediaMaxImageSizeMB<<20 {
+			return c.Status(http.StatusRequestEntityTooLarge).JSON(models.Error{Detail: constants.MediaTooLarge})
+		}
+
+		mediaType = models.MediaTypeImage
+		processedMedia, err = utils.ProcessImage(content, contentType)
+	case utils.IsVideoMediaType(contentType):
+		if len(content) > config.Config.MediaMaxVideoSizeMB<<20 {
+			return c.Status(http.StatusRequestEntityTooLarge).JSON(models.Error{Detail: constants.MediaTooLarge})
+		}
+
+		mediaType = models.MediaTypeVideo
+		processedMedia, err = utils.ProcessVideo(ctx, content, contentType)
+	default:
+		err = utils.ErrMediaTypeNotSupported
+	}
+
+	if err != nil {
+		fiberErr := mediaError(err)
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	mediaID := primitive.NewObjectID()
+	key := "media/" + mediaID.Hex() + "/original." + processedMedia.Extension
+	thumbnailKey := "media/" + mediaID.Hex() + "/thumbnail.jpg"
+
+	if err := storage.DefaultStore.Put(ctx, key, processedMedia.ContentType, bytes.NewReader(processedMedia.Content)); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	if err := storage.DefaultStore.Put(ctx, thumbnailKey, "image/jpeg", bytes.NewReader(processedMedia.Thumbnail)); err != nil {
+		storage.DefaultStore.Delete(ctx, key)
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	mediaResponse, err := crud.InsertMedia(models.MediaCreate{
+		ID:           mediaID,
+		UserID:       currentUser.ID,
+		Type:         mediaType,
+		ContentType:  processedMedia.ContentType,
+		Key:          key,
+		ThumbnailKey: thumbnailKey,
+		Url:          storage.DefaultStore.URL(key),
+		ThumbnailUrl: storage.DefaultStore.URL(thumbnailKey),
+		Width:        processedMedia.Width,
+		Height:       processedMedia.Height,
+		Duration:     processedMedia.Duration,
+		Size:         len(processedMedia.Content),
+	})
+	if err != nil {
+		storage.DefaultStore.Delete(ctx, key)
+		storage.DefaultStore.Delete(ctx, thumbnailKey)
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusCreated).JSON(mediaResponse)
+}
+
+// @Tags Media
+// @Summary Get Media File
+// @Description Download a file of a media, with the token of the URL given along with the post or the media
+// @Produce octet-stream
+// @Param media_id path string true "Media id"
+// @Param file_name path string true "File name"
+// @Param token query string true "Media token"
+// @Success 200 {file} file
+// @Failure default {object} models.Error
+// @Router /uploads/media/{media_id}/{file_name} [get]
+func GetMediaFile(c *fiber.Ctx) error {
+	params := struct {
+		MediaID  primitive.ObjectID `params:"mediaId"`
+		FileName string             `params:"fileName"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	claims, err := utils.GetAudienceJwtClaims(c.Query("token"), utils.MediaAudience)
+	if err != nil || claims.Subject != params.MediaID.Hex() {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	file, err := storage.DefaultStore.Open(c.Context(), "media/"+params.MediaID.Hex()+"/"+params.FileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.MediaNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	// Caches must not keep the file past its token
+	c.Type(filepath.Ext(params.FileName))
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", int(time.Until(claims.ExpiresAt.Time).Seconds())))
+
+	return c.SendStream(file)
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Conversations
+// @Summary Get Conversation Messages
+// @Description Get the messages of a conversation from the newest backwards, pass nextCursor as cursor to get older messages
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {object} models.MessagesPage
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/messages [get]
+// @Security ApiKeyAuth
+func GetConversationMessages(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Cursor primitive.ObjectID `query:"cursor"`
+		Limit  int                `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, fiberErr := currentConversation(params.ConversationID, currentUser); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	messagesPage, err := crud.FindAllConversationMessages(params.ConversationID, query.Cursor, int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(messagesPage)
+}
+
+// @Tags Conversations
+// @Summary Create Message
+// @Description Send a message, participants must accept a message request before replying
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Param body body models.MessageCreate true "Body"
+// @Success 201 {object} models.MessageResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/messages [post]
+// @Security ApiKeyAuth
+func CreateMessage(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.MessageCreate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body.ConversationID = params.ConversationID
+	body.SenderID = currentUser.ID
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	conversationResponse, fiberErr := currentConversation(params.ConversationID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if findConversationParticipant(conversationResponse, currentUser.ID).Status != models.ParticipantStatusAccepted {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.MessageRequestPending})
+	}
+
+	if !conversationResponse.IsGroup {
+		for _, participant := range conversationResponse.Participants {
+			if participant.UserID == currentUser.ID {
+				continue
+			}
+
+			if _, err := crud.FindOneBlockBetweenUsers(currentUser.ID, participant.UserID); err == nil {
+				return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.UserBlocked})
+			}
+		}
+	}
+
+	messageResponse, err := crud.InsertMessage(body)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	events.Publish(conversationParticipantIDs(conversationResponse), models.EventTypeMessageCreated, messageResponse)
+
+	return c.Status(http.StatusCreated).JSON(messageResponse)
+}
+
+// currentMessage returns a message of a conversation sent by the current user
+func currentMessage(conversationID primitive.ObjectID, messageID primitive.ObjectID, currentUser models.UserResponse) (models.ConversationResponse, models.MessageResponse, *fiber.Error) {
+	conversationResponse, fiberErr := currentConversation(conversationID, currentUser)
+	if fiberErr != nil {
+		return conversationResponse, models.MessageResponse{}, fiberErr
+	}
+
+	messageResponse, err := crud.FindOneMessageById(messageID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return conversationResponse, messageResponse, fiber.NewError(http.StatusNotFound, constants.MessageNotFound)
+		} else {
+			return conversationResponse, messageResponse, fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if messageResponse.ConversationID != conversationID {
+		return conversationResponse, messageResponse, fiber.NewError(http.StatusNotFound, constants.MessageNotFound)
+	}
+
+	if messageResponse.SenderID != currentUser.ID {
+		return conversationResponse, messageResponse, fiber.NewError(http.StatusForbidden, constants.InsufficientPrivileges)
+	}
+
+	if messageResponse.DeletedAt != nil {
+		return conversationResponse, messageResponse, fiber.NewError(http.StatusConflict, constants.MessageNotEditable)
+	}
+
+	return conversationResponse, messageResponse, nil
+}
+
+// @Tags Conversations
+// @Summary Update Message
+// @Description Edit a message sent by the current user
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Param message_id path string true "Message id"
+// @Param body body models.MessageUpdate true "Body"
+// @Success 200 {object} models.MessageResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/messages/{message_id} [patch]
+// @Security ApiKeyAuth
+func UpdateMessage(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+		MessageID      primitive.ObjectID `params:"messageId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	body := models.MessageUpdate{}
+
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&body); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	conversationResponse, _, fiberErr := currentMessage(params.ConversationID, params.MessageID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	messageResponse, err := crud.UpdateMessage(params.MessageID, body)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	events.Publish(conversationParticipantIDs(conversationResponse), models.EventTypeMessageUpdated, messageResponse)
+
+	return c.Status(http.StatusOK).JSON(messageResponse)
+}
+
+// @Tags Conversations
+// @Summary Delete Message
+// @Description Delete a message sent by the current user, it is kept in the history without content
+// @Accept json
+// @Produce json
+// @Param conversation_id path string true "Conversation id"
+// @Param message_id path string true "Message id"
+// @Success 200 {object} models.MessageResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/conversations/{conversation_id}/messages/{message_id} [delete]
+// @Security ApiKeyAuth
+func DeleteMessage(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		ConversationID primitive.ObjectID `params:"conversationId"`
+		MessageID      primitive.ObjectID `params:"messageId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	conversationResponse, messageResponse, fiberErr := currentMessage(params.ConversationID, params.MessageID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	messageResponse, err := crud.DeleteMessage(params.MessageID, messageResponse)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	events.Publish(conversationParticipantIDs(conversationResponse), models.EventTypeMessageDeleted, messageResponse)
+
+	return c.Status(http.StatusOK).JSON(messageResponse)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Account
+// @Summary Get Muted Users
+// @Description Get the users muted by the current user
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/account/mutes [get]
+// @Security ApiKeyAuth
+func GetMutedUsers(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	usersResponse, err := crud.FindAllMutedUsers(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(usersResponse)
+}
+
+// @Tags Users
+// @Summary Create Mute
+// @Description Mute a user, which hides their posts from the feeds of the current user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 201 {object} models.MuteResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/mute [post]
+// @Security ApiKeyAuth
+func CreateMute(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	if params.UserID == currentUser.ID {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.UserIsCurrentUser})
+	}
+
+	if _, err := crud.FindOneUserById(params.UserID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if _, err := crud.FindOneMuteByUserIds(currentUser.ID, params.UserID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.MuteAlreadyRegistered})
+	}
+
+	muteResponse, err := crud.InsertMute(models.MuteCreate{MuterID: currentUser.ID, MutedID: params.UserID})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.MuteAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusCreated).JSON(muteResponse)
+}
+
+// @Tags Users
+// @Summary Delete Mute
+// @Description Unmute a user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/mute [delete]
+// @Security ApiKeyAuth
+func DeleteMute(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	muteResponse, err := crud.FindOneMuteByUserIds(currentUser.ID, params.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.MuteNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if err := crud.DeleteMute(muteResponse.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.UserUnmuted})
+}
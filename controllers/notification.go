@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// @Tags Notifications
+// @Summary Get Notifications
+// @Description Get the notifications of the current user, most recent first
+// @Accept json
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.NotificationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/notifications [get]
+// @Security ApiKeyAuth
+func GetNotifications(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Unread bool `query:"unread"`
+		Skip   int  `query:"skip"`
+		Limit  int  `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	notificationsResponse, err := crud.FindAllNotificationsByUserId(currentUser.ID, query.Unread, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(notificationsResponse)
+}
+
+// @Tags Notifications
+// @Summary Get Unread Notifications Count
+// @Description Get the number of unread notifications of the current user
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.NotificationsCount
+// @Failure default {object} models.Error
+// @Router /api/v1/notifications/unread-count [get]
+// @Security ApiKeyAuth
+func GetUnreadNotificationsCount(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	count, err := crud.CountUnreadNotificationsByUserId(currentUser.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.NotificationsCount{Count: int(count)})
+}
+
+// @Tags Notifications
+// @Summary Read Notification
+// @Description Mark a notification as read
+// @Accept json
+// @Produce json
+// @Param notification_id path string true "Notification id"
+// @Success 200 {object} models.NotificationResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/notifications/{notification_id}/read [post]
+// @Security ApiKeyAuth
+func ReadNotification(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		NotificationID primitive.ObjectID `params:"notificationId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	notificationResponse, err := crud.FindOneNotificationById(params.NotificationID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.NotificationNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	// Notifications of other users are reported as missing rather than forbidden
+	if notificationResponse.UserID != currentUser.ID {
+		return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.NotificationNotFound})
+	}
+
+	notificationResponse, err = crud.MarkNotificationRead(params.NotificationID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(notificationResponse)
+}
+
+// @Tags Notifications
+// @Summary Read All Notifications
+// @Description Mark every notification of the current user as read
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/notifications/read [post]
+// @Security ApiKeyAuth
+func ReadAllNotifications(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if err := crud.MarkAllNotificationsRead(currentUser.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.NotificationsRead})
+}
@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
 	"github.com/wilfredohq/fiber-start/constants"
 	"github.com/wilfredohq/fiber-start/crud"
 	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/notifications"
 	"github.com/wilfredohq/fiber-start/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -26,7 +30,8 @@ import (
 // @Router /api/v1/posts [get]
 // @Security ApiKeyAuth
 func GetPosts(c *fiber.Ctx) error {
-	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
@@ -48,7 +53,7 @@ func GetPosts(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
-	postsResponse, err := crud.FindAllPosts(query.UserID, query.Search, int64(query.Skip), int64(query.Limit))
+	postsResponse, err := crud.FindAllPosts(currentUser.ID, query.UserID, query.Search, int64(query.Skip), int64(query.Limit))
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
@@ -58,7 +63,7 @@ func GetPosts(c *fiber.Ctx) error {
 
 // @Tags Posts
 // @Summary Create Post
-// @Description Create post
+// @Description Create post, with up to 4 media uploaded beforehand. Drafts and scheduled posts are only visible to their authors, scheduled posts are published at publishAt.
 // @Accept json
 // @Produce json
 // @Param body body models.PostCreate true "Body"
@@ -73,24 +78,60 @@ func CreatePost(c *fiber.Ctx) error {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
-	body := models.PostCreate{
-		UserID: currentUser.ID,
-	}
+	body := models.PostCreate{}
 
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
+	body.UserID = currentUser.ID
+
 	validate := utils.NewValidator()
 	if err := validate.Struct(&body); err != nil {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
+	// Only scheduled posts have a publication time
+	if body.Status != nil && *body.Status == models.PostStatusScheduled {
+		if body.PublishAt == nil || !body.PublishAt.After(time.Now()) {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.PostPublishAtInvalid})
+		}
+	} else {
+		body.PublishAt = nil
+	}
+
+	if body.QuoteOfID != nil {
+		originalResponse, err := crud.FindOnePostById(*body.QuoteOfID, currentUser.ID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+			} else {
+				return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+			}
+		}
+
+		// Quoting a repost quotes the reposted post
+		if originalResponse.RepostOfID != nil {
+			body.QuoteOfID = originalResponse.RepostOfID
+		}
+
+		if !isShareable(originalResponse, currentUser.ID) {
+			return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.PostNotShareable})
+		}
+	}
+
 	postResponse, err := crud.InsertPost(body)
 	if err != nil {
+		if errors.Is(err, crud.ErrMediaNotAttachable) {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.MediaNotAttachable})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
+	if postResponse.Status == models.PostStatusPublished {
+		go notifications.AnnouncePost(postResponse)
+	}
+
 	return c.Status(http.StatusCreated).JSON(postResponse)
 }
 
@@ -148,7 +189,8 @@ func GetHomePosts(c *fiber.Ctx) error {
 // @Router /api/v1/posts/{post_id} [get]
 // @Security ApiKeyAuth
 func GetPost(c *fiber.Ctx) error {
-	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
@@ -160,7 +202,7 @@ func GetPost(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
-	postResponse, err := crud.FindOnePostById(params.PostID)
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
@@ -174,7 +216,7 @@ func GetPost(c *fiber.Ctx) error {
 
 // @Tags Posts
 // @Summary Delete Post
-// @Description Delete post
+// @Description Delete post, it can be restored until it is purged. Reposts are deleted for good.
 // @Accept json
 // @Produce json
 // @Param post_id path string true "Post id"
@@ -196,7 +238,7 @@ func DeletePost(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
-	postResponse, err := crud.FindOnePostById(params.PostID)
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
@@ -209,16 +251,228 @@ func DeletePost(c *fiber.Ctx) error {
 		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
 	}
 
-	if err := crud.DeletePost(params.PostID); err != nil {
+	// Reposts have nothing worth restoring
+	if postResponse.RepostOfID != nil {
+		err = crud.DeletePost(params.PostID)
+	} else {
+		err = crud.SoftDeletePost(params.PostID, currentUser.ID)
+	}
+	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
 	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.PostDeleted})
 }
 
+// @Tags Posts
+// @Summary Get Deleted Posts
+// @Description Get the deleted posts of the current user that are not purged yet, superusers can get those of any user
+// @Accept json
+// @Produce json
+// @Param userId query string false "User id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/deleted [get]
+// @Security ApiKeyAuth
+func GetDeletedPosts(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		UserID primitive.ObjectID `query:"userId"`
+		Skip   int                `query:"skip"`
+		Limit  int                `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if query.UserID.IsZero() {
+		query.UserID = currentUser.ID
+	}
+
+	if query.UserID != currentUser.ID && !currentUser.IsSuperuser {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	postsResponse, err := crud.FindAllDeletedPostsByUserId(currentUser.ID, query.UserID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(postsResponse)
+}
+
+// @Tags Posts
+// @Summary Restore Post
+// @Description Restore a deleted post. Authors can restore the posts they deleted themselves for a limited time, superusers can restore any post until it is purged.
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 200 {object} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/restore [post]
+// @Security ApiKeyAuth
+func RestorePost(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	post, err := crud.FindOneDeletedPostById(params.PostID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if !currentUser.IsSuperuser {
+		// Other users' deleted posts don't exist for them
+		if post.UserID != currentUser.ID {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		}
+
+		// Posts removed by a moderator stay removed
+		restoreWindow := time.Duration(config.Config.PostRestoreDays) * 24 * time.Hour
+		if post.DeletedBy != currentUser.ID || time.Since(post.DeletedAt) > restoreWindow {
+			return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.PostNotRestorable})
+		}
+	}
+
+	postResponse, err := crud.RestorePost(params.PostID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(postResponse)
+}
+
+// @Tags Posts
+// @Summary Get Drafts
+// @Description Get the drafts and scheduled posts of the current user, last edited first
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/drafts [get]
+// @Security ApiKeyAuth
+func GetDrafts(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	postsResponse, err := crud.FindAllUnpublishedPostsByUserId(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(postsResponse)
+}
+
+// @Tags Posts
+// @Summary Publish Post
+// @Description Publish a draft or a scheduled post now
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 200 {object} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/publish [post]
+// @Security ApiKeyAuth
+func PublishPost(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if postResponse.UserID != currentUser.ID {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	if postResponse.Status == models.PostStatusPublished {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.PostAlreadyPublished})
+	}
+
+	postResponse, err = crud.PublishPost(params.PostID)
+	if err != nil {
+		// The scheduler got there first
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.PostAlreadyPublished})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	go notifications.AnnouncePost(postResponse)
+
+	return c.Status(http.StatusOK).JSON(postResponse)
+}
+
 // @Tags Posts
 // @Summary Update Post
-// @Description Update post
+// @Description Update post, the replaced content is kept as a revision. Posts can't be edited once the edit window is over, drafts and scheduled posts can be edited until they are published.
 // @Accept json
 // @Produce json
 // @Param post_id path string true "Post id"
@@ -242,7 +496,7 @@ func UpdatePost(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
-	postResponse, err := crud.FindOnePostById(params.PostID)
+	postResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
@@ -255,6 +509,16 @@ func UpdatePost(c *fiber.Ctx) error {
 		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
 	}
 
+	if postResponse.RepostOfID != nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.RepostNotEditable})
+	}
+
+	isPublished := postResponse.Status == models.PostStatusPublished
+
+	if editWindow := time.Duration(config.Config.PostEditWindowMinutes) * time.Minute; isPublished && editWindow > 0 && time.Since(postResponse.CreatedAt) > editWindow {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.PostNotEditable})
+	}
+
 	body := models.PostUpdate{}
 
 	if err := c.BodyParser(&body); err != nil {
@@ -266,10 +530,222 @@ func UpdatePost(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
-	postResponse, err = crud.UpdatePost(params.PostID, body)
+	if body.Status != nil || body.PublishAt != nil {
+		if isPublished {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.PostAlreadyPublished})
+		}
+
+		// The schedule is checked as it will be saved
+		status, publishAt := postResponse.Status, postResponse.PublishAt
+		if body.Status != nil {
+			status = *body.Status
+		}
+		if body.PublishAt != nil {
+			publishAt = body.PublishAt
+		}
+
+		if status == models.PostStatusScheduled && (publishAt == nil || !publishAt.After(time.Now())) {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.Error{Detail: constants.PostPublishAtInvalid})
+		}
+		if status != models.PostStatusScheduled {
+			body.PublishAt = nil
+		}
+	}
+
+	previousMentions := postResponse.Mentions
+
+	postResponse, err = crud.UpdatePost(params.PostID, currentUser.ID, body)
 	if err != nil {
+		if errors.Is(err, crud.ErrPostAlreadyPublished) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.PostAlreadyPublished})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
+	// Mentions in drafts are notified once they are published
+	if isPublished {
+		go notifications.NotifyMentionedUsers(postResponse, previousMentions)
+	}
+
 	return c.Status(http.StatusOK).JSON(postResponse)
 }
+
+// @Tags Posts
+// @Summary Get Post Revisions
+// @Description Get the earlier versions of an edited post, newest first
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.PostRevisionResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/revisions [get]
+// @Security ApiKeyAuth
+func GetPostRevisions(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	if _, err := crud.FindOnePostById(params.PostID, currentUser.ID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	postRevisionsResponse, err := crud.FindAllPostRevisionsByPostId(params.PostID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(postRevisionsResponse)
+}
+
+// @Tags Posts
+// @Summary Create Repost
+// @Description Share a post unchanged with the current user's followers
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 201 {object} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/reposts [post]
+// @Security ApiKeyAuth
+func CreateRepost(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	originalResponse, err := crud.FindOnePostById(params.PostID, currentUser.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.PostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	// Reposting a repost reposts the original post
+	originalID := originalResponse.ID
+	if originalResponse.RepostOfID != nil {
+		originalID = *originalResponse.RepostOfID
+	}
+
+	if !isShareable(originalResponse, currentUser.ID) {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.PostNotShareable})
+	}
+
+	if _, err := crud.FindOneRepostByUserAndPostIds(currentUser.ID, originalID); err == nil {
+		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.RepostAlreadyRegistered})
+	}
+
+	content := ""
+	postCreate := models.PostCreate{
+		UserID:     currentUser.ID,
+		Content:    &content,
+		RepostOfID: &originalID,
+	}
+
+	postResponse, err := crud.InsertPost(postCreate)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.RepostAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	go notifications.AnnouncePost(postResponse)
+
+	return c.Status(http.StatusCreated).JSON(postResponse)
+}
+
+// @Tags Posts
+// @Summary Delete Repost
+// @Description Undo the current user's repost of a post
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/posts/{post_id}/reposts [delete]
+// @Security ApiKeyAuth
+func DeleteRepost(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		PostID primitive.ObjectID `params:"postId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	repostResponse, err := crud.FindOneRepostByUserAndPostIds(currentUser.ID, params.PostID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.RepostNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if err := crud.DeletePost(repostResponse.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.RepostDeleted})
+}
+
+// isShareable reports whether a post can be reposted or quoted, the posts of private accounts are only shared by
+// their authors and unpublished posts are not shared at all
+func isShareable(postResponse models.PostResponse, userID primitive.ObjectID) bool {
+	if postResponse.Status != models.PostStatusPublished {
+		return false
+	}
+
+	authorID, author := postResponse.UserID, postResponse.User
+	if postResponse.RepostOfID != nil && postResponse.Original != nil {
+		authorID, author = postResponse.Original.UserID, postResponse.Original.User
+	}
+
+	return !author.IsPrivate || authorID == userID
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/storage"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// profileImageSizes are the sizes uploaded avatars and covers are cropped and resized to
+var profileImageSizes = map[string][2]int{
+	models.ProfileImageTypeAvatar: {400, 400},
+	models.ProfileImageTypeCover:  {1500, 500},
+}
+
+// editableUser returns the user, which only the user and superusers can edit
+func editableUser(userID primitive.ObjectID, currentUser models.UserResponse) (models.UserResponse, *fiber.Error) {
+	userResponse, err := crud.FindOneUserById(userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.UserResponse{}, fiber.NewError(http.StatusNotFound, constants.UserNotFound)
+		} else {
+			return models.UserResponse{}, fiber.NewError(http.StatusInternalServerError, constants.InternalServerError)
+		}
+	}
+
+	if userResponse.ID != currentUser.ID && !currentUser.IsSuperuser {
+		return models.UserResponse{}, fiber.NewError(http.StatusForbidden, constants.InsufficientPrivileges)
+	}
+
+	return userResponse, nil
+}
+
+func updateProfileImage(c *fiber.Ctx, imageType string) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	userResponse, fiberErr := editableUser(params.UserID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	content, err := uploadedFile(c)
+	if err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	if len(content) > config.Config.MediaMaxImageSizeMB<<20 {
+		return c.Status(http.StatusRequestEntityTooLarge).JSON(models.Error{Detail: constants.MediaTooLarge})
+	}
+
+	size := profileImageSizes[imageType]
+
+	processedImage, err := utils.ProcessProfileImage(content, utils.SniffMediaType(content), size[0], size[1])
+	if err != nil {
+		fiberErr := mediaError(err)
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Every upload gets a new key, so the files can be cached forever
+	key := imageType + "s/" + userResponse.ID.Hex() + "/" + primitive.NewObjectID().Hex() + "." + processedImage.Extension
+
+	if err := storage.DefaultStore.Put(ctx, key, processedImage.ContentType, bytes.NewReader(processedImage.Content)); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	// The file is only left unused when the user wasn't updated
+	if err := crud.UpdateUserProfileImage(userResponse.ID, imageType, key, storage.DefaultStore.URL(key)); err != nil {
+		storage.DefaultStore.Delete(ctx, key)
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	userResponse, err = crud.FindOneUserById(userResponse.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(userResponse)
+}
+
+func deleteProfileImage(c *fiber.Ctx, imageType string) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	userResponse, fiberErr := editableUser(params.UserID, currentUser)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	if err := crud.UpdateUserProfileImage(userResponse.ID, imageType, "", ""); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	userResponse, err := crud.FindOneUserById(userResponse.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(userResponse)
+}
+
+// @Tags Users
+// @Summary Update User Avatar
+// @Description Upload the avatar of a user, the image is cropped to a square and resized to 400x400
+// @Accept mpfd
+// @Produce json
+// @Param user_id path string true "User id"
+// @Param file formData file true "File"
+// @Success 200 {object} models.UserResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/avatar [put]
+// @Security ApiKeyAuth
+func UpdateUserAvatar(c *fiber.Ctx) error {
+	return updateProfileImage(c, models.ProfileImageTypeAvatar)
+}
+
+// @Tags Users
+// @Summary Delete User Avatar
+// @Description Remove the avatar of a user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/avatar [delete]
+// @Security ApiKeyAuth
+func DeleteUserAvatar(c *fiber.Ctx) error {
+	return deleteProfileImage(c, models.ProfileImageTypeAvatar)
+}
+
+// @Tags Users
+// @Summary Update User Cover
+// @Description Upload the cover of a user, the image is cropped to 3:1 and resized to 1500x500
+// @Accept mpfd
+// @Produce json
+// @Param user_id path string true "User id"
+// @Param file formData file true "File"
+// @Success 200 {object} models.UserResponse
+// @Failure 422 {object} models.ValidationError
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/cover [put]
+// @Security ApiKeyAuth
+func UpdateUserCover(c *fiber.Ctx) error {
+	return updateProfileImage(c, models.ProfileImageTypeCover)
+}
+
+// @Tags Users
+// @Summary Delete User Cover
+// @Description Remove the cover of a user
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/cover [delete]
+// @Security ApiKeyAuth
+func DeleteUserCover(c *fiber.Ctx) error {
+	return deleteProfileImage(c, models.ProfileImageTypeCover)
+}
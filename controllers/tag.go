@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+)
+
+var trendingWindows = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+// @Tags Tags
+// @Summary Get Tag Posts
+// @Description Get posts with a hashtag
+// @Accept json
+// @Produce json
+// @Param tag path string true "Tag, without #"
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.PostResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/tags/{tag}/posts [get]
+// @Security ApiKeyAuth
+func GetTagPosts(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		Tag string `params:"tag"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	tag := strings.ToLower(strings.TrimPrefix(params.Tag, "#"))
+
+	postsResponse, err := crud.FindAllPostsByTag(currentUser.ID, tag, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(postsResponse)
+}
+
+// @Tags Tags
+// @Summary Get Trending Tags
+// @Description Get tags ranked by time-decayed usage over the last hour, day or week
+// @Accept json
+// @Produce json
+// @Param window query string false "Window" Enums(hour, day, week) default(day)
+// @Param limit query int false "Limit" default(10) minimum(1) maximum(100)
+// @Success 200 {array} models.TrendingTag
+// @Failure default {object} models.Error
+// @Router /api/v1/tags/trending [get]
+// @Security ApiKeyAuth
+func GetTrendingTags(c *fiber.Ctx) error {
+	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Window string `query:"window" validate:"oneof=hour day week"`
+		Limit  int    `query:"limit" validate:"min=1,max=100"`
+	}{
+		Window: "day",
+		Limit:  10,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	trendingTags, err := crud.FindTrendingTags(trendingWindows[query.Window], int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(trendingTags)
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/wilfredohq/fiber-start/config"
@@ -28,7 +30,8 @@ import (
 // @Router /api/v1/users [get]
 // @Security ApiKeyAuth
 func GetUsers(c *fiber.Ctx) error {
-	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
@@ -51,7 +54,17 @@ func GetUsers(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
-	usersResponse, err := crud.FindAllUsers(query.FollowerID, query.FollowedID, query.Search, int64(query.Skip), int64(query.Limit))
+	// Listing the followed users or the followers of someone goes through the same checks as their follow lists
+	for _, userID := range []primitive.ObjectID{query.FollowerID, query.FollowedID} {
+		if userID.IsZero() {
+			continue
+		}
+		if fiberErr := checkFollowListAccess(userID, currentUser); fiberErr != nil {
+			return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+		}
+	}
+
+	usersResponse, err := crud.FindAllUsers(currentUser.ID, query.FollowerID, query.FollowedID, query.Search, int64(query.Skip), int64(query.Limit))
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
@@ -59,6 +72,47 @@ func GetUsers(c *fiber.Ctx) error {
 	return c.Status(http.StatusOK).JSON(usersResponse)
 }
 
+// @Tags Users
+// @Summary Get User Suggestions
+// @Description Get users the current user might want to follow, with the reason of each suggestion
+// @Accept json
+// @Produce json
+// @Param skip query int false "Skip" default(0)
+// @Param limit query int false "Limit" default(20) minimum(1)
+// @Success 200 {array} models.UserSuggestionResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/suggestions [get]
+// @Security ApiKeyAuth
+func GetUserSuggestions(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	query := struct {
+		Skip  int `query:"skip"`
+		Limit int `query:"limit" validate:"min=1"`
+	}{
+		Limit: 20,
+	}
+
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	validate := utils.NewValidator()
+	if err := validate.Struct(&query); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
+	}
+
+	userSuggestionsResponse, err := crud.FindUserSuggestions(currentUser.ID, int64(query.Skip), int64(query.Limit))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(userSuggestionsResponse)
+}
+
 // @Tags Users
 // @Summary Create User
 // @Description Create user
@@ -87,10 +141,27 @@ func CreateUser(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: utils.ValidatorErrors(err)})
 	}
 
+	passwordErrors, err := utils.PasswordPolicyErrors("password", *body.Password, *body.Email, *body.FullName)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+	if passwordErrors != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: passwordErrors})
+	}
+
 	if _, err := crud.FindOneUserByEmail(*body.Email); err == nil {
 		return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.UserAlreadyRegistered})
 	}
 
+	if body.Handle != nil {
+		handle := strings.ToLower(*body.Handle)
+		body.Handle = &handle
+
+		if _, err := crud.FindOneUserByHandle(handle); err == nil {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.HandleAlreadyRegistered})
+		}
+	}
+
 	if config.Config.UsersOpenRegistration {
 		isActive := true
 		isSuperuser := false
@@ -101,6 +172,10 @@ func CreateUser(c *fiber.Ctx) error {
 
 	userResponse, err := crud.InsertUser(body)
 	if err != nil {
+		// The handle was taken since it was checked
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.HandleAlreadyRegistered})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
 
@@ -120,7 +195,8 @@ func CreateUser(c *fiber.Ctx) error {
 // @Router /api/v1/users/{user_id} [get]
 // @Security ApiKeyAuth
 func GetUser(c *fiber.Ctx) error {
-	if _, fiberErr := currentActiveUser(c); fiberErr != nil {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
 		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
 	}
 
@@ -132,6 +208,15 @@ func GetUser(c *fiber.Ctx) error {
 		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
 	}
 
+	// Users that blocked each other don't see each other
+	if !currentUser.IsSuperuser {
+		if _, err := crud.FindOneBlockBetweenUsers(currentUser.ID, params.UserID); err == nil {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else if err != mongo.ErrNoDocuments {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
 	userResponse, err := crud.FindOneUserById(params.UserID)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -196,9 +281,148 @@ func UpdateUser(c *fiber.Ctx) error {
 
 	if !currentUser.IsSuperuser {
 		body.IsSuperuser = &userResponse.IsSuperuser
+		// Users change their own password through /account/password
+		body.Password = nil
+	}
+
+	if body.Password != nil {
+		fullName := userResponse.FullName
+		if body.FullName != nil {
+			fullName = *body.FullName
+		}
+
+		passwordErrors, err := utils.PasswordPolicyErrors("password", *body.Password, userResponse.Email, fullName)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+		if passwordErrors != nil {
+			return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: passwordErrors})
+		}
+	}
+
+	if body.Handle != nil {
+		handle := strings.ToLower(*body.Handle)
+		body.Handle = &handle
+
+		if handleUserResponse, err := crud.FindOneUserByHandle(handle); err == nil && handleUserResponse.ID != userResponse.ID {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.HandleAlreadyRegistered})
+		}
 	}
 
 	userResponse, err = crud.UpdateUser(params.UserID, body)
+	if err != nil {
+		// The handle was taken since it was checked
+		if mongo.IsDuplicateKeyError(err) {
+			return c.Status(http.StatusConflict).JSON(models.Error{Detail: constants.HandleAlreadyRegistered})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	if body.Password != nil {
+		// Whoever knew the old password must not keep a session
+		sessionsRevokedAt := time.Now().Truncate(time.Millisecond)
+
+		userResponse, err = crud.UpdateUserPassword(params.UserID, *body.Password, sessionsRevokedAt)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(userResponse)
+}
+
+// @Tags Users
+// @Summary Delete User
+// @Description Delete user, after the configured grace period when users delete themselves
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.Msg
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id} [delete]
+// @Security ApiKeyAuth
+func DeleteUser(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	userResponse, err := crud.FindOneUserById(params.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if userResponse.ID != currentUser.ID && !currentUser.IsSuperuser {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	if userResponse.ID == currentUser.ID && config.Config.UserDeletionGracePeriodDays > 0 {
+		deletionScheduledAt := time.Now().AddDate(0, 0, config.Config.UserDeletionGracePeriodDays)
+
+		if _, err := crud.ScheduleUserDeletion(userResponse.ID, deletionScheduledAt); err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+
+		return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.UserDeletionScheduled})
+	}
+
+	if err := crud.DeleteUser(userResponse.ID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+	}
+
+	return c.Status(http.StatusOK).JSON(models.Msg{Msg: constants.UserDeleted})
+}
+
+// @Tags Users
+// @Summary Restore User
+// @Description Cancel a scheduled user deletion
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User id"
+// @Success 200 {object} models.UserResponse
+// @Failure default {object} models.Error
+// @Router /api/v1/users/{user_id}/restore [post]
+// @Security ApiKeyAuth
+func RestoreUser(c *fiber.Ctx) error {
+	currentUser, fiberErr := currentActiveUser(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(models.Error{Detail: fiberErr.Message})
+	}
+
+	params := struct {
+		UserID primitive.ObjectID `params:"userId"`
+	}{}
+
+	if err := c.ParamsParser(&params); err != nil {
+		return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+	}
+
+	userResponse, err := crud.FindOneUserById(params.UserID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(http.StatusNotFound).JSON(models.Error{Detail: constants.UserNotFound})
+		} else {
+			return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
+		}
+	}
+
+	if userResponse.ID != currentUser.ID && !currentUser.IsSuperuser {
+		return c.Status(http.StatusForbidden).JSON(models.Error{Detail: constants.InsufficientPrivileges})
+	}
+
+	userResponse, err = crud.CancelUserDeletion(userResponse.ID)
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(models.Error{Detail: constants.InternalServerError})
 	}
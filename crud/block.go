@@ -0,0 +1,212 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertBlock also removes the follower relations between both users in either direction
+func InsertBlock(blockCreate models.BlockCreate) (models.BlockResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.BlockResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	blockCollection := db.GetCollection(db.DB, "blocks")
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	blockCreate.CreatedAt = time.Now()
+	blockCreate.UpdatedAt = time.Now()
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := blockCollection.InsertOne(sessCtx, blockCreate)
+		if err != nil {
+			return nil, err
+		}
+
+		followerRelations, err := findFollowerRelations(sessCtx, bson.M{"$or": []bson.M{
+			{"followerId": blockCreate.BlockerID, "followedId": blockCreate.BlockedID},
+			{"followerId": blockCreate.BlockedID, "followedId": blockCreate.BlockerID},
+		}})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, followerRelation := range followerRelations {
+			if _, err := followerRelationCollection.DeleteOne(sessCtx, bson.M{"_id": followerRelation.ID}); err != nil {
+				return nil, err
+			}
+
+			if followerRelation.Status == models.FollowerRelationStatusPending {
+				continue
+			}
+
+			if err := UpdateUserFollowersCount(sessCtx, followerRelation.FollowedID, -1); err != nil {
+				return nil, err
+			}
+
+			if err := UpdateUserFollowingCount(sessCtx, followerRelation.FollowerID, -1); err != nil {
+				return nil, err
+			}
+		}
+
+		return result.InsertedID, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, opts)
+	if err != nil {
+		return models.BlockResponse{}, err
+	}
+
+	return FindOneBlockById(result.(primitive.ObjectID))
+}
+
+func findOneBlock(filter interface{}, opts ...*options.FindOneOptions) (models.BlockResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blockCollection := db.GetCollection(db.DB, "blocks")
+
+	blockResponse := models.BlockResponse{}
+
+	if err := blockCollection.FindOne(ctx, filter, opts...).Decode(&blockResponse); err != nil {
+		return models.BlockResponse{}, err
+	}
+
+	return blockResponse, nil
+}
+
+func FindOneBlockById(blockID primitive.ObjectID) (models.BlockResponse, error) {
+	filter := bson.M{"_id": blockID}
+
+	return findOneBlock(filter)
+}
+
+func FindOneBlockByUserIds(blockerID primitive.ObjectID, blockedID primitive.ObjectID) (models.BlockResponse, error) {
+	filter := bson.M{"blockerId": blockerID, "blockedId": blockedID}
+
+	return findOneBlock(filter)
+}
+
+// FindOneBlockBetweenUsers finds a block in either direction
+func FindOneBlockBetweenUsers(userID primitive.ObjectID, otherUserID primitive.ObjectID) (models.BlockResponse, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"blockerId": userID, "blockedId": otherUserID},
+		{"blockerId": otherUserID, "blockedId": userID},
+	}}
+
+	return findOneBlock(filter)
+}
+
+func FindAllBlockedUsers(blockerID primitive.ObjectID, skip int64, limit int64) ([]models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blockCollection := db.GetCollection(db.DB, "blocks")
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"blockerId": blockerID}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "blockedId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$replaceRoot": bson.M{"newRoot": "$user"}},
+	}
+
+	cur, err := blockCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	usersResponse := []models.UserResponse{}
+
+	if err := cur.All(ctx, &usersResponse); err != nil {
+		return nil, err
+	}
+
+	return usersResponse, nil
+}
+
+func DeleteBlock(blockID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	blockCollection := db.GetCollection(db.DB, "blocks")
+
+	filter := bson.M{"_id": blockID}
+
+	if _, err := blockCollection.DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// findHiddenUserIds returns the users whose content the viewer must not see: those blocked by the
+// viewer or blocking them and, for feeds, those muted by the viewer
+func findHiddenUserIds(ctx context.Context, viewerID primitive.ObjectID, includeMuted bool) ([]primitive.ObjectID, error) {
+	blockCollection := db.GetCollection(db.DB, "blocks")
+	muteCollection := db.GetCollection(db.DB, "mutes")
+
+	hiddenUserIDs := []primitive.ObjectID{}
+
+	blocks := []models.Block{}
+
+	cur, err := blockCollection.Find(ctx, bson.M{"$or": []bson.M{{"blockerId": viewerID}, {"blockedId": viewerID}}})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cur.All(ctx, &blocks); err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		if block.BlockerID == viewerID {
+			hiddenUserIDs = append(hiddenUserIDs, block.BlockedID)
+		} else {
+			hiddenUserIDs = append(hiddenUserIDs, block.BlockerID)
+		}
+	}
+
+	if !includeMuted {
+		return hiddenUserIDs, nil
+	}
+
+	mutes := []models.Mute{}
+
+	cur, err = muteCollection.Find(ctx, bson.M{"muterId": viewerID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cur.All(ctx, &mutes); err != nil {
+		return nil, err
+	}
+
+	for _, mute := range mutes {
+		hiddenUserIDs = append(hiddenUserIDs, mute.MutedID)
+	}
+
+	return hiddenUserIDs, nil
+}
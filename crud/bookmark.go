@@ -0,0 +1,360 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertBookmark(bookmarkCreate models.BookmarkCreate) (models.BookmarkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	bookmarkCreate.CreatedAt = time.Now()
+	bookmarkCreate.UpdatedAt = time.Now()
+
+	result, err := bookmarkCollection.InsertOne(ctx, bookmarkCreate)
+	if err != nil {
+		return models.BookmarkResponse{}, err
+	}
+
+	return FindOneBookmarkById(result.InsertedID.(primitive.ObjectID))
+}
+
+func findOneBookmark(filter interface{}, opts ...*options.FindOneOptions) (models.BookmarkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	bookmarkResponse := models.BookmarkResponse{}
+
+	if err := bookmarkCollection.FindOne(ctx, filter, opts...).Decode(&bookmarkResponse); err != nil {
+		return models.BookmarkResponse{}, err
+	}
+
+	return bookmarkResponse, nil
+}
+
+func FindOneBookmarkById(bookmarkID primitive.ObjectID) (models.BookmarkResponse, error) {
+	filter := bson.M{"_id": bookmarkID}
+
+	return findOneBookmark(filter)
+}
+
+func FindOneBookmarkByUserAndPostIds(userID primitive.ObjectID, postID primitive.ObjectID) (models.BookmarkResponse, error) {
+	filter := bson.M{"userId": userID, "postId": postID}
+
+	return findOneBookmark(filter)
+}
+
+func FindAllBookmarksByUserId(userID primitive.ObjectID) ([]models.BookmarkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	filter := bson.M{"userId": userID}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	cur, err := bookmarkCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarksResponse := []models.BookmarkResponse{}
+
+	if err := cur.All(ctx, &bookmarksResponse); err != nil {
+		return nil, err
+	}
+
+	return bookmarksResponse, nil
+}
+
+// FindAllBookmarkedPosts returns the posts bookmarked by the user from the newest bookmark backwards, optionally only
+// the ones of a collection. Posts that the user can't see anymore are left out of the page but still move the cursor.
+func FindAllBookmarkedPosts(userID primitive.ObjectID, collectionID primitive.ObjectID, cursor primitive.ObjectID, limit int64) (models.BookmarksPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	filter := bson.M{"userId": userID}
+	if !collectionID.IsZero() {
+		filter["collectionId"] = collectionID
+	}
+	if !cursor.IsZero() {
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit)
+
+	cur, err := bookmarkCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return models.BookmarksPage{}, err
+	}
+
+	bookmarks := []models.Bookmark{}
+
+	if err := cur.All(ctx, &bookmarks); err != nil {
+		return models.BookmarksPage{}, err
+	}
+
+	bookmarksPage := models.BookmarksPage{Data: []models.PostResponse{}}
+
+	if int64(len(bookmarks)) == limit {
+		nextCursor := bookmarks[len(bookmarks)-1].ID
+		bookmarksPage.NextCursor = &nextCursor
+	}
+
+	if len(bookmarks) == 0 {
+		return bookmarksPage, nil
+	}
+
+	postIDs := []primitive.ObjectID{}
+	for _, bookmark := range bookmarks {
+		postIDs = append(postIDs, bookmark.PostID)
+	}
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, userID, false)
+	if err != nil {
+		return models.BookmarksPage{}, err
+	}
+
+	visibilityMatch, err := postVisibilityMatch(ctx, userID)
+	if err != nil {
+		return models.BookmarksPage{}, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"_id": bson.M{"$in": postIDs}, "userId": bson.M{"$nin": hiddenUserIDs}, "deletedAt": nil, "status": publishedPostStatus()}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$match": visibilityMatch},
+	}
+	pipeline = append(pipeline, postResponseStages(userID, hiddenUserIDs)...)
+
+	postsResponse, err := findPosts(pipeline)
+	if err != nil {
+		return models.BookmarksPage{}, err
+	}
+
+	// Posts are returned in the order they were bookmarked
+	postsResponseByID := map[primitive.ObjectID]models.PostResponse{}
+	for _, postResponse := range postsResponse {
+		postsResponseByID[postResponse.ID] = postResponse
+	}
+
+	for _, bookmark := range bookmarks {
+		if postResponse, ok := postsResponseByID[bookmark.PostID]; ok {
+			bookmarksPage.Data = append(bookmarksPage.Data, postResponse)
+		}
+	}
+
+	return bookmarksPage, nil
+}
+
+func UpdateBookmark(bookmarkID primitive.ObjectID, bookmarkUpdate models.BookmarkUpdate) (models.BookmarkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	bookmarkUpdate.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": bookmarkID}
+	update := bson.M{"$set": bookmarkUpdate}
+
+	// Without a collection the bookmark goes back to the unsorted ones
+	if bookmarkUpdate.CollectionID == nil {
+		update["$unset"] = bson.M{"collectionId": ""}
+	}
+
+	if _, err := bookmarkCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.BookmarkResponse{}, err
+	}
+
+	return FindOneBookmarkById(bookmarkID)
+}
+
+func DeleteBookmark(bookmarkID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+
+	filter := bson.M{"_id": bookmarkID}
+
+	if _, err := bookmarkCollection.DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func InsertBookmarkCollection(bookmarkCollectionCreate models.BookmarkCollectionCreate) (models.BookmarkCollectionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollectionCollection := db.GetCollection(db.DB, "bookmarkCollections")
+
+	bookmarkCollectionCreate.CreatedAt = time.Now()
+	bookmarkCollectionCreate.UpdatedAt = time.Now()
+
+	result, err := bookmarkCollectionCollection.InsertOne(ctx, bookmarkCollectionCreate)
+	if err != nil {
+		return models.BookmarkCollectionResponse{}, err
+	}
+
+	return FindOneBookmarkCollectionById(result.InsertedID.(primitive.ObjectID))
+}
+
+func bookmarkCollectionResponseStages() []bson.M {
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from": "bookmarks",
+			"let":  bson.M{"collectionId": "$_id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$collectionId", "$$collectionId"}}}},
+				{"$count": "count"},
+			},
+			"as": "bookmarks",
+		}},
+		{"$addFields": bson.M{
+			"bookmarksCount": bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$bookmarks.count", 0}}, 0}},
+		}},
+		{"$project": bson.M{"bookmarks": 0}},
+	}
+}
+
+func findBookmarkCollections(pipeline interface{}) ([]models.BookmarkCollectionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollectionCollection := db.GetCollection(db.DB, "bookmarkCollections")
+
+	cur, err := bookmarkCollectionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarkCollectionsResponse := []models.BookmarkCollectionResponse{}
+
+	if err := cur.All(ctx, &bookmarkCollectionsResponse); err != nil {
+		return nil, err
+	}
+
+	return bookmarkCollectionsResponse, nil
+}
+
+func findOneBookmarkCollection(match interface{}) (models.BookmarkCollectionResponse, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$limit": 1},
+	}
+	pipeline = append(pipeline, bookmarkCollectionResponseStages()...)
+
+	bookmarkCollectionsResponse, err := findBookmarkCollections(pipeline)
+	if err != nil {
+		return models.BookmarkCollectionResponse{}, err
+	}
+
+	if len(bookmarkCollectionsResponse) == 0 {
+		return models.BookmarkCollectionResponse{}, mongo.ErrNoDocuments
+	}
+
+	return bookmarkCollectionsResponse[0], nil
+}
+
+func FindOneBookmarkCollectionById(bookmarkCollectionID primitive.ObjectID) (models.BookmarkCollectionResponse, error) {
+	match := bson.M{"_id": bookmarkCollectionID}
+
+	return findOneBookmarkCollection(match)
+}
+
+func FindOneBookmarkCollectionByUserIdAndName(userID primitive.ObjectID, name string) (models.BookmarkCollectionResponse, error) {
+	match := bson.M{"userId": userID, "name": name}
+
+	return findOneBookmarkCollection(match)
+}
+
+func FindAllBookmarkCollectionsByUserId(userID primitive.ObjectID, skip int64, limit int64) ([]models.BookmarkCollectionResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"userId": userID}},
+		{"$sort": bson.M{"name": 1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, bookmarkCollectionResponseStages()...)
+
+	return findBookmarkCollections(pipeline)
+}
+
+func UpdateBookmarkCollection(bookmarkCollectionID primitive.ObjectID, bookmarkCollectionUpdate models.BookmarkCollectionUpdate) (models.BookmarkCollectionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookmarkCollectionCollection := db.GetCollection(db.DB, "bookmarkCollections")
+
+	bookmarkCollectionUpdate.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": bookmarkCollectionID}
+	update := bson.M{"$set": bookmarkCollectionUpdate}
+
+	if _, err := bookmarkCollectionCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.BookmarkCollectionResponse{}, err
+	}
+
+	return FindOneBookmarkCollectionById(bookmarkCollectionID)
+}
+
+// DeleteBookmarkCollection removes a collection, its bookmarks are kept without a collection
+func DeleteBookmarkCollection(bookmarkCollectionID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+	bookmarkCollectionCollection := db.GetCollection(db.DB, "bookmarkCollections")
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"collectionId": bookmarkCollectionID}
+		update := bson.M{"$unset": bson.M{"collectionId": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+
+		if _, err := bookmarkCollection.UpdateMany(sessCtx, filter, update); err != nil {
+			return nil, err
+		}
+
+		if _, err := bookmarkCollectionCollection.DeleteOne(sessCtx, bson.M{"_id": bookmarkCollectionID}); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertComment(commentCreate models.CommentCreate) (models.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.CommentResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	if commentCreate.AncestorIDs == nil {
+		commentCreate.AncestorIDs = []primitive.ObjectID{}
+	}
+	commentCreate.CreatedAt = time.Now()
+	commentCreate.UpdatedAt = time.Now()
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := commentCollection.InsertOne(sessCtx, commentCreate)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := UpdatePostCommentsCount(sessCtx, commentCreate.PostID, 1); err != nil {
+			return nil, err
+		}
+
+		if commentCreate.ParentID != nil {
+			if err := updateCommentRepliesCount(sessCtx, *commentCreate.ParentID, 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return result.InsertedID, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, opts)
+	if err != nil {
+		return models.CommentResponse{}, err
+	}
+
+	return FindOneCommentById(result.(primitive.ObjectID))
+}
+
+func commentUserStages() []bson.M {
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+	}
+}
+
+func findOneComment(match interface{}, opts ...*options.AggregateOptions) (models.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	pipeline := append([]bson.M{{"$match": match}}, commentUserStages()...)
+
+	cur, err := commentCollection.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return models.CommentResponse{}, err
+	}
+
+	commentResponse := models.CommentResponse{}
+
+	if cur.Next(ctx) {
+		if err := cur.Decode(&commentResponse); err != nil {
+			return models.CommentResponse{}, err
+		}
+	} else {
+		return models.CommentResponse{}, mongo.ErrNoDocuments
+	}
+
+	return commentResponse, nil
+}
+
+func FindOneCommentById(commentID primitive.ObjectID) (models.CommentResponse, error) {
+	match := bson.M{"_id": commentID}
+
+	return findOneComment(match)
+}
+
+func FindAllCommentsByUserId(userID primitive.ObjectID) ([]models.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"userId": userID}},
+		{"$sort": bson.M{"createdAt": -1}},
+	}
+	pipeline = append(pipeline, commentUserStages()...)
+
+	cur, err := commentCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsResponse := []models.CommentResponse{}
+
+	if err := cur.All(ctx, &commentsResponse); err != nil {
+		return nil, err
+	}
+
+	return commentsResponse, nil
+}
+
+// findCommentsPage returns up to limit comments after the cursor, oldest first
+func findCommentsPage(match bson.M, cursor primitive.ObjectID, limit int64) (models.CommentsPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	if !cursor.IsZero() {
+		match["_id"] = bson.M{"$gt": cursor}
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"_id": 1}},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, commentUserStages()...)
+
+	cur, err := commentCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return models.CommentsPage{}, err
+	}
+
+	commentsPage := models.CommentsPage{Data: []models.CommentResponse{}}
+
+	if err := cur.All(ctx, &commentsPage.Data); err != nil {
+		return models.CommentsPage{}, err
+	}
+
+	if int64(len(commentsPage.Data)) == limit {
+		nextCursor := commentsPage.Data[len(commentsPage.Data)-1].ID
+		commentsPage.NextCursor = &nextCursor
+	}
+
+	return commentsPage, nil
+}
+
+func FindAllPostComments(postID primitive.ObjectID, cursor primitive.ObjectID, limit int64) (models.CommentsPage, error) {
+	match := bson.M{"postId": postID, "parentId": bson.M{"$exists": false}}
+
+	return findCommentsPage(match, cursor, limit)
+}
+
+func FindAllCommentReplies(commentID primitive.ObjectID, cursor primitive.ObjectID, limit int64) (models.CommentsPage, error) {
+	match := bson.M{"parentId": commentID}
+
+	return findCommentsPage(match, cursor, limit)
+}
+
+func UpdateComment(commentID primitive.ObjectID, commentUpdate models.CommentUpdate) (models.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	commentUpdate.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": commentID}
+	update := bson.M{"$set": commentUpdate}
+
+	if _, err := commentCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.CommentResponse{}, err
+	}
+
+	return FindOneCommentById(commentID)
+}
+
+func updateCommentRepliesCount(ctx context.Context, commentID primitive.ObjectID, repliesCountDelta int) error {
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	filter := bson.M{"_id": commentID}
+	update := bson.M{"$inc": bson.M{"repliesCount": repliesCountDelta}}
+
+	if _, err := commentCollection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deleteCommentTree removes a comment with all of its replies and keeps the counters in sync
+func deleteCommentTree(ctx context.Context, commentID primitive.ObjectID, postID primitive.ObjectID, parentID *primitive.ObjectID) error {
+	commentCollection := db.GetCollection(db.DB, "comments")
+
+	filter := bson.M{"$or": []bson.M{{"_id": commentID}, {"ancestorIds": commentID}}}
+
+	result, err := commentCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return nil
+	}
+
+	if err := UpdatePostCommentsCount(ctx, postID, -int(result.DeletedCount)); err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		if err := updateCommentRepliesCount(ctx, *parentID, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func DeleteComment(commentID primitive.ObjectID, commentResponse models.CommentResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, deleteCommentTree(sessCtx, commentID, commentResponse.PostID, commentResponse.ParentID)
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
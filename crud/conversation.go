@@ -0,0 +1,279 @@
+package crud
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetDirectConversationKey identifies the one-to-one conversation between two users
+func GetDirectConversationKey(userID primitive.ObjectID, otherUserID primitive.ObjectID) string {
+	hexIDs := []string{userID.Hex(), otherUserID.Hex()}
+	sort.Strings(hexIDs)
+
+	return strings.Join(hexIDs, ":")
+}
+
+func InsertConversation(conversationCreate models.ConversationCreate) (models.ConversationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	conversationCreate.LastMessageAt = time.Now()
+	conversationCreate.CreatedAt = time.Now()
+	conversationCreate.UpdatedAt = time.Now()
+
+	result, err := conversationCollection.InsertOne(ctx, conversationCreate)
+	if err != nil {
+		return models.ConversationResponse{}, err
+	}
+
+	return FindOneConversationById(result.InsertedID.(primitive.ObjectID), conversationCreate.CreatorID)
+}
+
+// conversationResponseStages adds the participant users and the unread count of the viewer
+func conversationResponseStages(viewerID primitive.ObjectID) []bson.M {
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "participants.userId",
+			"foreignField": "_id",
+			"as":           "users",
+		}},
+		{"$addFields": bson.M{
+			"unreadCount": bson.M{"$sum": bson.M{"$map": bson.M{
+				"input": bson.M{"$filter": bson.M{
+					"input": "$participants",
+					"cond":  bson.M{"$eq": []interface{}{"$$this.userId", viewerID}},
+				}},
+				"in": "$$this.unreadCount",
+			}}},
+		}},
+	}
+}
+
+func findConversations(pipeline interface{}, opts ...*options.AggregateOptions) ([]models.ConversationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	cur, err := conversationCollection.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationsResponse := []models.ConversationResponse{}
+
+	if err := cur.All(ctx, &conversationsResponse); err != nil {
+		return nil, err
+	}
+
+	return conversationsResponse, nil
+}
+
+func findOneConversation(match interface{}, viewerID primitive.ObjectID) (models.ConversationResponse, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$limit": 1},
+	}
+	pipeline = append(pipeline, conversationResponseStages(viewerID)...)
+
+	conversationsResponse, err := findConversations(pipeline)
+	if err != nil {
+		return models.ConversationResponse{}, err
+	}
+
+	if len(conversationsResponse) == 0 {
+		return models.ConversationResponse{}, mongo.ErrNoDocuments
+	}
+
+	return conversationsResponse[0], nil
+}
+
+func FindOneConversationById(conversationID primitive.ObjectID, viewerID primitive.ObjectID) (models.ConversationResponse, error) {
+	match := bson.M{"_id": conversationID}
+
+	return findOneConversation(match, viewerID)
+}
+
+// FindOneDirectConversation returns the one-to-one conversation of the key, only to one of its participants
+func FindOneDirectConversation(directKey string, viewerID primitive.ObjectID) (models.ConversationResponse, error) {
+	match := bson.M{"directKey": directKey, "isGroup": false, "participants.userId": viewerID}
+
+	return findOneConversation(match, viewerID)
+}
+
+func FindAllConversationsByUserId(userID primitive.ObjectID, status string, skip int64, limit int64) ([]models.ConversationResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"participants": bson.M{"$elemMatch": bson.M{"userId": userID, "status": status}}}},
+		{"$sort": bson.M{"lastMessageAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, conversationResponseStages(userID)...)
+
+	return findConversations(pipeline)
+}
+
+func AcceptConversation(conversationID primitive.ObjectID, userID primitive.ObjectID) (models.ConversationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	filter := bson.M{"_id": conversationID, "participants.userId": userID}
+	update := bson.M{"$set": bson.M{"participants.$.status": models.ParticipantStatusAccepted, "updatedAt": time.Now()}}
+
+	if _, err := conversationCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.ConversationResponse{}, err
+	}
+
+	return FindOneConversationById(conversationID, userID)
+}
+
+// ReadConversation moves the read receipt of a participant forward to a message and recounts the
+// messages of others left unread after it
+func ReadConversation(conversationID primitive.ObjectID, userID primitive.ObjectID, messageID primitive.ObjectID) (models.ConversationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	unreadCount, err := messageCollection.CountDocuments(ctx, bson.M{
+		"conversationId": conversationID,
+		"_id":            bson.M{"$gt": messageID},
+		"senderId":       bson.M{"$ne": userID},
+		"deletedAt":      bson.M{"$exists": false},
+	})
+	if err != nil {
+		return models.ConversationResponse{}, err
+	}
+
+	filter := bson.M{
+		"_id": conversationID,
+		"participants": bson.M{"$elemMatch": bson.M{
+			"userId": userID,
+			"$or": []bson.M{
+				{"lastReadMessageId": nil},
+				{"lastReadMessageId": bson.M{"$lt": messageID}},
+			},
+		}},
+	}
+	update := bson.M{"$set": bson.M{
+		"participants.$.lastReadMessageId": messageID,
+		"participants.$.lastReadAt":        time.Now(),
+		"participants.$.unreadCount":       unreadCount,
+	}}
+
+	if _, err := conversationCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.ConversationResponse{}, err
+	}
+
+	return FindOneConversationById(conversationID, userID)
+}
+
+// deleteConversations removes the matching conversations together with their messages
+func deleteConversations(ctx context.Context, filter bson.M) error {
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	conversations := []models.Conversation{}
+
+	cur, err := conversationCollection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if err := cur.All(ctx, &conversations); err != nil {
+		return err
+	}
+
+	conversationIDs := []primitive.ObjectID{}
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+	}
+
+	if len(conversationIDs) == 0 {
+		return nil
+	}
+
+	if _, err := messageCollection.DeleteMany(ctx, bson.M{"conversationId": bson.M{"$in": conversationIDs}}); err != nil {
+		return err
+	}
+
+	if _, err := conversationCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": conversationIDs}}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// abandonedConversationsFilter matches one-to-one conversations missing a participant and empty groups
+func abandonedConversationsFilter() bson.M {
+	return bson.M{"$or": []bson.M{
+		{"isGroup": false, "participants.1": bson.M{"$exists": false}},
+		{"isGroup": true, "participants.0": bson.M{"$exists": false}},
+	}}
+}
+
+// removeConversationParticipant takes users out of the matching conversations and deletes the
+// conversations left abandoned
+func removeConversationParticipant(ctx context.Context, filter bson.M, userID primitive.ObjectID) error {
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	update := bson.M{"$pull": bson.M{"participants": bson.M{"userId": userID}}}
+
+	if _, err := conversationCollection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return deleteConversations(ctx, abandonedConversationsFilter())
+}
+
+func LeaveConversation(conversationID primitive.ObjectID, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	filter := bson.M{"_id": conversationID}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, removeConversationParticipant(sessCtx, filter, userID)
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deleteUserConversations removes a user from their conversations and deletes the messages they sent
+func deleteUserConversations(ctx context.Context, userID primitive.ObjectID) error {
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	if _, err := messageCollection.DeleteMany(ctx, bson.M{"senderId": userID}); err != nil {
+		return err
+	}
+
+	return removeConversationParticipant(ctx, bson.M{"participants.userId": userID}, userID)
+}
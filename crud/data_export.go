@@ -0,0 +1,211 @@
+package crud
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertDataExport(dataExportCreate models.DataExportCreate) (models.DataExportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	dataExportCreate.Status = models.DataExportStatusPending
+	dataExportCreate.CreatedAt = time.Now()
+	dataExportCreate.UpdatedAt = time.Now()
+
+	result, err := dataExportCollection.InsertOne(ctx, dataExportCreate)
+	if err != nil {
+		return models.DataExportResponse{}, err
+	}
+
+	return FindOneDataExportById(result.InsertedID.(primitive.ObjectID))
+}
+
+func findOneDataExport(filter interface{}, opts ...*options.FindOneOptions) (models.DataExportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	dataExportResponse := models.DataExportResponse{}
+
+	if err := dataExportCollection.FindOne(ctx, filter, opts...).Decode(&dataExportResponse); err != nil {
+		return models.DataExportResponse{}, err
+	}
+
+	return dataExportResponse, nil
+}
+
+func FindOneDataExportById(dataExportID primitive.ObjectID) (models.DataExportResponse, error) {
+	filter := bson.M{"_id": dataExportID}
+
+	return findOneDataExport(filter)
+}
+
+func FindOneUnfinishedDataExportByUserId(userID primitive.ObjectID) (models.DataExportResponse, error) {
+	filter := bson.M{
+		"userId": userID,
+		"status": bson.M{"$in": []string{models.DataExportStatusPending, models.DataExportStatusProcessing}},
+	}
+
+	return findOneDataExport(filter)
+}
+
+func FindExpiredDataExports(now time.Time) ([]models.DataExportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	filter := bson.M{"status": models.DataExportStatusCompleted, "expiresAt": bson.M{"$lte": now}}
+
+	cur, err := dataExportCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExportsResponse := []models.DataExportResponse{}
+
+	if err := cur.All(ctx, &dataExportsResponse); err != nil {
+		return nil, err
+	}
+
+	return dataExportsResponse, nil
+}
+
+func updateDataExport(dataExportID primitive.ObjectID, fields bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	fields["updatedAt"] = time.Now()
+
+	filter := bson.M{"_id": dataExportID}
+	update := bson.M{"$set": fields}
+
+	if _, err := dataExportCollection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StartDataExport marks a data export as processing, every start counts as an attempt
+func StartDataExport(dataExportID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	filter := bson.M{"_id": dataExportID}
+	update := bson.M{
+		"$set": bson.M{"status": models.DataExportStatusProcessing, "updatedAt": time.Now()},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	if _, err := dataExportCollection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimStaleDataExport starts again an unfinished data export that was left untouched since the given time, by an
+// instance that stopped while building it for example
+func ClaimStaleDataExport(staleBefore time.Time) (models.DataExportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	filter := bson.M{
+		"status":    bson.M{"$in": []string{models.DataExportStatusPending, models.DataExportStatusProcessing}},
+		"updatedAt": bson.M{"$lt": staleBefore},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": models.DataExportStatusProcessing, "updatedAt": time.Now()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	dataExportResponse := models.DataExportResponse{}
+
+	if err := dataExportCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&dataExportResponse); err != nil {
+		return models.DataExportResponse{}, err
+	}
+
+	return dataExportResponse, nil
+}
+
+func UpdateDataExportStatus(dataExportID primitive.ObjectID, status string) error {
+	return updateDataExport(dataExportID, bson.M{"status": status})
+}
+
+func CompleteDataExport(dataExportID primitive.ObjectID, fileName string, content []byte, expiresAt time.Time) error {
+	bucket, err := db.GetBucket(db.DB, "dataExports")
+	if err != nil {
+		return err
+	}
+
+	fileID, err := bucket.UploadFromStream(fileName, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	return updateDataExport(dataExportID, bson.M{
+		"status":      models.DataExportStatusCompleted,
+		"fileId":      fileID,
+		"completedAt": time.Now(),
+		"expiresAt":   expiresAt,
+	})
+}
+
+func DownloadDataExportFile(fileID primitive.ObjectID, stream io.Writer) error {
+	bucket, err := db.GetBucket(db.DB, "dataExports")
+	if err != nil {
+		return err
+	}
+
+	if _, err := bucket.DownloadToStream(fileID, stream); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func DeleteDataExport(dataExportResponse models.DataExportResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !dataExportResponse.FileID.IsZero() {
+		bucket, err := db.GetBucket(db.DB, "dataExports")
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.DeleteContext(ctx, dataExportResponse.FileID); err != nil {
+			return err
+		}
+	}
+
+	dataExportCollection := db.GetCollection(db.DB, "dataExports")
+
+	filter := bson.M{"_id": dataExportResponse.ID}
+
+	if _, err := dataExportCollection.DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// scheduleBlobDeletions queues the files for removal, within the same transaction that drops the references to them
+func scheduleBlobDeletions(ctx context.Context, keys ...string) error {
+	deletedBlobCollection := db.GetCollection(db.DB, "deletedBlobs")
+
+	deletedBlobs := []interface{}{}
+	for _, key := range keys {
+		if key != "" {
+			deletedBlobs = append(deletedBlobs, models.DeletedBlob{Key: key, CreatedAt: time.Now()})
+		}
+	}
+
+	if len(deletedBlobs) == 0 {
+		return nil
+	}
+
+	if _, err := deletedBlobCollection.InsertMany(ctx, deletedBlobs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func FindDeletedBlobs(limit int64) ([]models.DeletedBlob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deletedBlobCollection := db.GetCollection(db.DB, "deletedBlobs")
+
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(limit)
+
+	cur, err := deletedBlobCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedBlobs := []models.DeletedBlob{}
+
+	if err := cur.All(ctx, &deletedBlobs); err != nil {
+		return nil, err
+	}
+
+	return deletedBlobs, nil
+}
+
+func DeleteDeletedBlob(deletedBlobID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deletedBlobCollection := db.GetCollection(db.DB, "deletedBlobs")
+
+	if _, err := deletedBlobCollection.DeleteOne(ctx, bson.M{"_id": deletedBlobID}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrCannotFollowSelf   = errors.New("cannot follow self")
+	ErrFollowUserNotFound = errors.New("follow user not found")
+	ErrFollowUserInactive = errors.New("follow user inactive")
+)
+
+const (
+	InvalidFollowerRelationSelf      = "self_follow"
+	InvalidFollowerRelationMissing   = "missing_user"
+	InvalidFollowerRelationInactive  = "inactive_user"
+	InvalidFollowerRelationDuplicate = "duplicate"
+)
+
+// checkFollowGraphRules validates both ends of a follower relation before it is written and returns the followed user
+func checkFollowGraphRules(ctx context.Context, followerID primitive.ObjectID, followedID primitive.ObjectID) (models.User, error) {
+	if followerID == followedID {
+		return models.User{}, ErrCannotFollowSelf
+	}
+
+	userCollection := db.GetCollection(db.DB, "users")
+
+	cur, err := userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": []primitive.ObjectID{followerID, followedID}}})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	users := []models.User{}
+
+	if err := cur.All(ctx, &users); err != nil {
+		return models.User{}, err
+	}
+
+	if len(users) != 2 {
+		return models.User{}, ErrFollowUserNotFound
+	}
+
+	followedUser := models.User{}
+	for _, user := range users {
+		if !user.IsActive {
+			return models.User{}, ErrFollowUserInactive
+		}
+
+		if user.ID == followedID {
+			followedUser = user
+		}
+	}
+
+	return followedUser, nil
+}
+
+type InvalidFollowerRelation struct {
+	FollowerRelation models.FollowerRelationResponse
+	Reason           string
+}
+
+// FindInvalidFollowerRelations returns the relations written before the follow graph rules existed that break them,
+// relations with inactive users are only included on request since the users may be activated again
+func FindInvalidFollowerRelations(includeInactive bool) ([]InvalidFollowerRelation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	pipeline := []bson.M{
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "followerId",
+			"foreignField": "_id",
+			"as":           "follower",
+		}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "followedId",
+			"foreignField": "_id",
+			"as":           "followed",
+		}},
+		{"$addFields": bson.M{
+			"reason": bson.M{"$switch": bson.M{
+				"branches": []bson.M{
+					{"case": bson.M{"$eq": []string{"$followerId", "$followedId"}}, "then": InvalidFollowerRelationSelf},
+					{"case": bson.M{"$or": []bson.M{
+						{"$eq": []interface{}{bson.M{"$size": "$follower"}, 0}},
+						{"$eq": []interface{}{bson.M{"$size": "$followed"}, 0}},
+					}}, "then": InvalidFollowerRelationMissing},
+					{"case": bson.M{"$and": []interface{}{
+						includeInactive,
+						bson.M{"$in": []interface{}{false, bson.M{"$concatArrays": []string{"$follower.isActive", "$followed.isActive"}}}},
+					}}, "then": InvalidFollowerRelationInactive},
+				},
+				"default": "",
+			}},
+		}},
+		{"$match": bson.M{"reason": bson.M{"$ne": ""}}},
+		{"$project": bson.M{"follower": 0, "followed": 0}},
+	}
+
+	cur, err := followerRelationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidFollowerRelations := []InvalidFollowerRelation{}
+	invalidFollowerRelationIDs := map[primitive.ObjectID]bool{}
+
+	for cur.Next(ctx) {
+		invalidFollowerRelation := InvalidFollowerRelation{}
+
+		if err := cur.Decode(&invalidFollowerRelation.FollowerRelation); err != nil {
+			return nil, err
+		}
+		invalidFollowerRelation.Reason = cur.Current.Lookup("reason").StringValue()
+
+		invalidFollowerRelations = append(invalidFollowerRelations, invalidFollowerRelation)
+		invalidFollowerRelationIDs[invalidFollowerRelation.FollowerRelation.ID] = true
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	// The oldest relation between two users is kept, an accepted one over a pending one
+	pipeline = []bson.M{
+		{"$sort": bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{"$group": bson.M{
+			"_id":               bson.M{"followerId": "$followerId", "followedId": "$followedId"},
+			"followerRelations": bson.M{"$push": "$$ROOT"},
+		}},
+		{"$match": bson.M{"followerRelations.1": bson.M{"$exists": true}}},
+	}
+
+	cur, err = followerRelationCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []struct {
+		FollowerRelations []models.FollowerRelationResponse `bson:"followerRelations"`
+	}{}
+
+	if err := cur.All(ctx, &duplicates); err != nil {
+		return nil, err
+	}
+
+	for _, duplicate := range duplicates {
+		for _, followerRelation := range duplicate.FollowerRelations[1:] {
+			if invalidFollowerRelationIDs[followerRelation.ID] {
+				continue
+			}
+
+			invalidFollowerRelations = append(invalidFollowerRelations, InvalidFollowerRelation{
+				FollowerRelation: followerRelation,
+				Reason:           InvalidFollowerRelationDuplicate,
+			})
+		}
+	}
+
+	return invalidFollowerRelations, nil
+}
+
+// DeleteInvalidFollowerRelations removes the given relations and recounts the followers and following counters of the
+// users involved, it runs outside a transaction since the recount can be repeated safely
+func DeleteInvalidFollowerRelations(invalidFollowerRelations []InvalidFollowerRelation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	userCollection := db.GetCollection(db.DB, "users")
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	followerRelationIDs := []primitive.ObjectID{}
+	userIDs := map[primitive.ObjectID]bool{}
+	for _, invalidFollowerRelation := range invalidFollowerRelations {
+		followerRelationIDs = append(followerRelationIDs, invalidFollowerRelation.FollowerRelation.ID)
+		userIDs[invalidFollowerRelation.FollowerRelation.FollowerID] = true
+		userIDs[invalidFollowerRelation.FollowerRelation.FollowedID] = true
+	}
+
+	if _, err := followerRelationCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": followerRelationIDs}}); err != nil {
+		return err
+	}
+
+	for userID := range userIDs {
+		followersCount, err := followerRelationCollection.CountDocuments(ctx, bson.M{
+			"followedId": userID,
+			"status":     acceptedFollowerRelationStatus(),
+		})
+		if err != nil {
+			return err
+		}
+
+		followingCount, err := followerRelationCollection.CountDocuments(ctx, bson.M{
+			"followerId": userID,
+			"status":     acceptedFollowerRelationStatus(),
+		})
+		if err != nil {
+			return err
+		}
+
+		update := bson.M{"$set": bson.M{"followersCount": followersCount, "followingCount": followingCount}}
+
+		// Missing users are skipped by the update
+		if _, err := userCollection.UpdateOne(ctx, bson.M{"_id": userID}, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -28,11 +28,27 @@ func InsertFollowerRelation(followerRelationCreation models.FollowerRelationCrea
 	followerRelationCreation.UpdatedAt = time.Now()
 
 	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		followedUser, err := checkFollowGraphRules(sessCtx, followerRelationCreation.FollowerID, *followerRelationCreation.FollowedID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Private accounts approve their followers
+		followerRelationCreation.Status = models.FollowerRelationStatusAccepted
+		if followedUser.IsPrivate {
+			followerRelationCreation.Status = models.FollowerRelationStatusPending
+		}
+
 		result, err := followerRelationCollection.InsertOne(sessCtx, followerRelationCreation)
 		if err != nil {
 			return nil, err
 		}
 
+		// Follow requests only count once they are accepted
+		if followerRelationCreation.Status == models.FollowerRelationStatusPending {
+			return result.InsertedID, nil
+		}
+
 		if err := UpdateUserFollowersCount(sessCtx, *followerRelationCreation.FollowedID, 1); err != nil {
 			return nil, err
 		}
@@ -55,6 +71,41 @@ func InsertFollowerRelation(followerRelationCreation models.FollowerRelationCrea
 	return FindOneFollowerRelationById(result.(primitive.ObjectID))
 }
 
+// acceptedFollowerRelationStatus matches accepted relations, including the ones created before follow requests existed
+func acceptedFollowerRelationStatus() bson.M {
+	return bson.M{"$ne": models.FollowerRelationStatusPending}
+}
+
+// findFollowedUserIds returns the users whose private posts the follower can see
+func findFollowedUserIds(ctx context.Context, followerID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	followerRelations, err := findFollowerRelations(ctx, bson.M{"followerId": followerID, "status": acceptedFollowerRelationStatus()})
+	if err != nil {
+		return nil, err
+	}
+
+	followedIDs := []primitive.ObjectID{}
+	for _, followerRelation := range followerRelations {
+		followedIDs = append(followedIDs, followerRelation.FollowedID)
+	}
+
+	return followedIDs, nil
+}
+
+// findFollowerUserIds returns the users that follow the followed user
+func findFollowerUserIds(ctx context.Context, followedID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	followerRelations, err := findFollowerRelations(ctx, bson.M{"followedId": followedID, "status": acceptedFollowerRelationStatus()})
+	if err != nil {
+		return nil, err
+	}
+
+	followerIDs := []primitive.ObjectID{}
+	for _, followerRelation := range followerRelations {
+		followerIDs = append(followerIDs, followerRelation.FollowerID)
+	}
+
+	return followerIDs, nil
+}
+
 func findOneFollowerRelation(filter interface{}, opts ...*options.FindOneOptions) (models.FollowerRelationResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -82,6 +133,43 @@ func FindOneFollowerRelationByUserIds(followerID primitive.ObjectID, followedID
 	return findOneFollowerRelation(filter)
 }
 
+func findFollowerRelations(ctx context.Context, filter interface{}, opts ...*options.FindOptions) ([]models.FollowerRelationResponse, error) {
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	cur, err := followerRelationCollection.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	followerRelationsResponse := []models.FollowerRelationResponse{}
+
+	if err := cur.All(ctx, &followerRelationsResponse); err != nil {
+		return nil, err
+	}
+
+	return followerRelationsResponse, nil
+}
+
+func FindAllFollowerRelationsByFollowerId(followerID primitive.ObjectID) ([]models.FollowerRelationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"followerId": followerID, "status": acceptedFollowerRelationStatus()}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	return findFollowerRelations(ctx, filter, opts)
+}
+
+func FindAllFollowerRelationsByFollowedId(followedID primitive.ObjectID) ([]models.FollowerRelationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"followedId": followedID, "status": acceptedFollowerRelationStatus()}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	return findFollowerRelations(ctx, filter, opts)
+}
+
 func DeleteFollowerRelation(followerRelationID primitive.ObjectID, followerRelationResponse models.FollowerRelationResponse) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -101,6 +189,10 @@ func DeleteFollowerRelation(followerRelationID primitive.ObjectID, followerRelat
 			return nil, err
 		}
 
+		if followerRelationResponse.Status == models.FollowerRelationStatusPending {
+			return nil, nil
+		}
+
 		if err := UpdateUserFollowersCount(sessCtx, followerRelationResponse.FollowedID, -1); err != nil {
 			return nil, err
 		}
@@ -121,3 +213,282 @@ func DeleteFollowerRelation(followerRelationID primitive.ObjectID, followerRelat
 
 	return nil
 }
+
+func FindAllFollowRequests(followedID primitive.ObjectID, skip int64, limit int64) ([]models.FollowRequestResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"followedId": followedID, "status": models.FollowerRelationStatusPending}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "followerId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+	}
+
+	cur, err := followerRelationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	followRequestsResponse := []models.FollowRequestResponse{}
+
+	if err := cur.All(ctx, &followRequestsResponse); err != nil {
+		return nil, err
+	}
+
+	return followRequestsResponse, nil
+}
+
+func AcceptFollowerRelation(followerRelationID primitive.ObjectID, followerRelationResponse models.FollowerRelationResponse) (models.FollowerRelationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.FollowerRelationResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	filter := bson.M{"_id": followerRelationID, "status": models.FollowerRelationStatusPending}
+	update := bson.M{"$set": bson.M{"status": models.FollowerRelationStatusAccepted, "updatedAt": time.Now()}}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if _, err := checkFollowGraphRules(sessCtx, followerRelationResponse.FollowerID, followerRelationResponse.FollowedID); err != nil {
+			return nil, err
+		}
+
+		result, err := followerRelationCollection.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return nil, err
+		}
+
+		// Already accepted by a concurrent request
+		if result.ModifiedCount == 0 {
+			return nil, nil
+		}
+
+		if err := UpdateUserFollowersCount(sessCtx, followerRelationResponse.FollowedID, 1); err != nil {
+			return nil, err
+		}
+
+		if err := UpdateUserFollowingCount(sessCtx, followerRelationResponse.FollowerID, 1); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return models.FollowerRelationResponse{}, err
+	}
+
+	return FindOneFollowerRelationById(followerRelationID)
+}
+
+// acceptAllFollowRequests accepts every pending request of a user, used when the account stops being private
+func acceptAllFollowRequests(sessCtx mongo.SessionContext, followedID primitive.ObjectID) error {
+	userCollection := db.GetCollection(db.DB, "users")
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	followRequests, err := findFollowerRelations(sessCtx, bson.M{"followedId": followedID, "status": models.FollowerRelationStatusPending})
+	if err != nil {
+		return err
+	}
+
+	// Requests that break the follow graph rules stay pending
+	followerRelationIDs := []primitive.ObjectID{}
+	followerIDs := []primitive.ObjectID{}
+	for _, followRequest := range followRequests {
+		if _, err := checkFollowGraphRules(sessCtx, followRequest.FollowerID, followRequest.FollowedID); err != nil {
+			if err == ErrCannotFollowSelf || err == ErrFollowUserNotFound || err == ErrFollowUserInactive {
+				continue
+			}
+			return err
+		}
+
+		followerRelationIDs = append(followerRelationIDs, followRequest.ID)
+		followerIDs = append(followerIDs, followRequest.FollowerID)
+	}
+
+	if len(followerRelationIDs) == 0 {
+		return nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": followerRelationIDs}}
+	update := bson.M{"$set": bson.M{"status": models.FollowerRelationStatusAccepted, "updatedAt": time.Now()}}
+
+	if _, err := followerRelationCollection.UpdateMany(sessCtx, filter, update); err != nil {
+		return err
+	}
+
+	if err := UpdateUserFollowersCount(sessCtx, followedID, len(followerRelationIDs)); err != nil {
+		return err
+	}
+
+	filter = bson.M{"_id": bson.M{"$in": followerIDs}}
+	update = bson.M{"$inc": bson.M{"followingCount": 1}}
+
+	_, err = userCollection.UpdateMany(sessCtx, filter, update)
+	return err
+}
+
+// followUserStages replaces each relation with the user on the userField side of it, annotated with its relation
+// to the viewer
+func followUserStages(viewerID primitive.ObjectID, userField string) []bson.M {
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   userField,
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$lookup": bson.M{
+			"from": "followerRelations",
+			"let":  bson.M{"userId": "$user._id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"status": acceptedFollowerRelationStatus(),
+					"$expr": bson.M{"$or": []bson.M{
+						{"$and": []bson.M{
+							{"$eq": []interface{}{"$followerId", "$$userId"}},
+							{"$eq": []interface{}{"$followedId", viewerID}},
+						}},
+						{"$and": []bson.M{
+							{"$eq": []interface{}{"$followerId", viewerID}},
+							{"$eq": []interface{}{"$followedId", "$$userId"}},
+						}},
+					}},
+				}},
+			},
+			"as": "viewerRelations",
+		}},
+		{"$replaceWith": bson.M{"$mergeObjects": []interface{}{
+			"$user",
+			bson.M{
+				"followedAt": "$createdAt",
+				"followsYou": bson.M{"$in": []interface{}{viewerID, "$viewerRelations.followedId"}},
+				"youFollow":  bson.M{"$in": []interface{}{viewerID, "$viewerRelations.followerId"}},
+			},
+		}}},
+	}
+}
+
+func findFollowUsers(ctx context.Context, pipeline interface{}) ([]models.FollowUserResponse, error) {
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	cur, err := followerRelationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	followUsersResponse := []models.FollowUserResponse{}
+
+	if err := cur.All(ctx, &followUsersResponse); err != nil {
+		return nil, err
+	}
+
+	return followUsersResponse, nil
+}
+
+func FindAllFollowers(viewerID primitive.ObjectID, followedID primitive.ObjectID, skip int64, limit int64) ([]models.FollowUserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"followedId": followedID,
+			"followerId": bson.M{"$nin": hiddenUserIDs},
+			"status":     acceptedFollowerRelationStatus(),
+		}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, followUserStages(viewerID, "followerId")...)
+
+	return findFollowUsers(ctx, pipeline)
+}
+
+func FindAllFollowing(viewerID primitive.ObjectID, followerID primitive.ObjectID, skip int64, limit int64) ([]models.FollowUserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"followerId": followerID,
+			"followedId": bson.M{"$nin": hiddenUserIDs},
+			"status":     acceptedFollowerRelationStatus(),
+		}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, followUserStages(viewerID, "followedId")...)
+
+	return findFollowUsers(ctx, pipeline)
+}
+
+// FindAllMutuals returns the users that the user follows and that follow the user back
+func FindAllMutuals(viewerID primitive.ObjectID, userID primitive.ObjectID, skip int64, limit int64) ([]models.FollowUserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The relation back is looked up per followed user so large follower lists aren't loaded into the query
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"followerId": userID,
+			"followedId": bson.M{"$nin": hiddenUserIDs},
+			"status":     acceptedFollowerRelationStatus(),
+		}},
+		{"$lookup": bson.M{
+			"from": "followerRelations",
+			"let":  bson.M{"followedId": "$followedId"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"followedId": userID,
+					"status":     acceptedFollowerRelationStatus(),
+					"$expr":      bson.M{"$eq": []string{"$followerId", "$$followedId"}},
+				}},
+				{"$limit": 1},
+			},
+			"as": "followBack",
+		}},
+		{"$match": bson.M{"followBack": bson.M{"$ne": bson.A{}}}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, followUserStages(viewerID, "followedId")...)
+
+	return findFollowUsers(ctx, pipeline)
+}
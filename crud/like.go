@@ -0,0 +1,179 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertLike(likeCreate models.LikeCreate) (models.LikeResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.LikeResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	likeCollection := db.GetCollection(db.DB, "likes")
+
+	likeCreate.CreatedAt = time.Now()
+	likeCreate.UpdatedAt = time.Now()
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := likeCollection.InsertOne(sessCtx, likeCreate)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := UpdatePostLikesCount(sessCtx, likeCreate.PostID, 1); err != nil {
+			return nil, err
+		}
+
+		return result.InsertedID, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, opts)
+	if err != nil {
+		return models.LikeResponse{}, err
+	}
+
+	return FindOneLikeById(result.(primitive.ObjectID))
+}
+
+func findOneLike(filter interface{}, opts ...*options.FindOneOptions) (models.LikeResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	likeCollection := db.GetCollection(db.DB, "likes")
+
+	likeResponse := models.LikeResponse{}
+
+	if err := likeCollection.FindOne(ctx, filter, opts...).Decode(&likeResponse); err != nil {
+		return models.LikeResponse{}, err
+	}
+
+	return likeResponse, nil
+}
+
+func FindOneLikeById(likeID primitive.ObjectID) (models.LikeResponse, error) {
+	filter := bson.M{"_id": likeID}
+
+	return findOneLike(filter)
+}
+
+func FindOneLikeByUserAndPostIds(userID primitive.ObjectID, postID primitive.ObjectID) (models.LikeResponse, error) {
+	filter := bson.M{"userId": userID, "postId": postID}
+
+	return findOneLike(filter)
+}
+
+func FindAllLikesByUserId(userID primitive.ObjectID) ([]models.LikeResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	likeCollection := db.GetCollection(db.DB, "likes")
+
+	filter := bson.M{"userId": userID}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	cur, err := likeCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	likesResponse := []models.LikeResponse{}
+
+	if err := cur.All(ctx, &likesResponse); err != nil {
+		return nil, err
+	}
+
+	return likesResponse, nil
+}
+
+func FindAllLikersByPostId(postID primitive.ObjectID, skip int64, limit int64) ([]models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	likeCollection := db.GetCollection(db.DB, "likes")
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"postId": postID}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$replaceRoot": bson.M{"newRoot": "$user"}},
+	}
+
+	cur, err := likeCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	usersResponse := []models.UserResponse{}
+
+	if err := cur.All(ctx, &usersResponse); err != nil {
+		return nil, err
+	}
+
+	return usersResponse, nil
+}
+
+func DeleteLike(likeID primitive.ObjectID, likeResponse models.LikeResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	likeCollection := db.GetCollection(db.DB, "likes")
+
+	filter := bson.M{"_id": likeID}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := likeCollection.DeleteOne(sessCtx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		// A concurrent unlike already took the like and its count away
+		if result.DeletedCount == 0 {
+			return nil, nil
+		}
+
+		if err := UpdatePostLikesCount(sessCtx, likeResponse.PostID, -1); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
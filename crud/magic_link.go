@@ -0,0 +1,48 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func InsertMagicLink(magicLinkCreate models.MagicLinkCreate) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	magicLinkCollection := db.GetCollection(db.DB, "magicLinks")
+
+	magicLinkCreate.CreatedAt = time.Now()
+
+	if _, err := magicLinkCollection.InsertOne(ctx, magicLinkCreate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UseMagicLink marks an unused, unexpired link as used so that it can only be exchanged once
+func UseMagicLink(tokenHash string) (models.MagicLink, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	magicLinkCollection := db.GetCollection(db.DB, "magicLinks")
+
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": time.Now()}}
+
+	magicLink := models.MagicLink{}
+
+	if err := magicLinkCollection.FindOneAndUpdate(ctx, filter, update).Decode(&magicLink); err != nil {
+		return models.MagicLink{}, err
+	}
+
+	return magicLink, nil
+}
@@ -0,0 +1,266 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrMediaNotAttachable is returned when a post refers to media that is missing, owned by someone else or already
+// attached to another post
+var ErrMediaNotAttachable = errors.New("media not attachable")
+
+func InsertMedia(mediaCreate models.MediaCreate) (models.MediaResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	mediaCreate.CreatedAt = time.Now()
+	mediaCreate.UpdatedAt = time.Now()
+
+	result, err := mediaCollection.InsertOne(ctx, mediaCreate)
+	if err != nil {
+		return models.MediaResponse{}, err
+	}
+
+	return FindOneMediaById(result.InsertedID.(primitive.ObjectID))
+}
+
+func FindOneMediaById(mediaID primitive.ObjectID) (models.MediaResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	mediaResponse := models.MediaResponse{}
+
+	if err := mediaCollection.FindOne(ctx, bson.M{"_id": mediaID}).Decode(&mediaResponse); err != nil {
+		return models.MediaResponse{}, err
+	}
+
+	if err := signMediaUrls(mediaResponse.ID, &mediaResponse.Url, &mediaResponse.ThumbnailUrl); err != nil {
+		return models.MediaResponse{}, err
+	}
+
+	return mediaResponse, nil
+}
+
+func FindAllMediaByUserId(userID primitive.ObjectID) ([]models.MediaResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	filter := bson.M{"userId": userID}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	cur, err := mediaCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaResponse := []models.MediaResponse{}
+
+	if err := cur.All(ctx, &mediaResponse); err != nil {
+		return nil, err
+	}
+
+	for i := range mediaResponse {
+		if err := signMediaUrls(mediaResponse[i].ID, &mediaResponse[i].Url, &mediaResponse[i].ThumbnailUrl); err != nil {
+			return nil, err
+		}
+	}
+
+	return mediaResponse, nil
+}
+
+// signMediaUrls adds a short-lived token to the URLs of the files of a media, they are only served with one. The
+// stored URLs stay unsigned, the token is given to whoever can see the media when it is read.
+func signMediaUrls(mediaID primitive.ObjectID, urls ...*string) error {
+	token, err := utils.GetAudienceJwt(mediaID.Hex(), utils.MediaAudience, config.Config.MediaLinkExpirationMinutes)
+	if err != nil {
+		return err
+	}
+
+	for _, url := range urls {
+		if *url != "" {
+			*url += "?token=" + token
+		}
+	}
+
+	return nil
+}
+
+// signPostAttachmentUrls signs the URLs of the attachments of a post and of the post it reposts or quotes
+func signPostAttachmentUrls(postResponse *models.PostResponse) error {
+	if postResponse.Original != nil {
+		if err := signAttachmentUrls(postResponse.Original.Attachments); err != nil {
+			return err
+		}
+	}
+
+	return signAttachmentUrls(postResponse.Attachments)
+}
+
+func signAttachmentUrls(attachments []models.PostAttachment) error {
+	for i := range attachments {
+		if err := signMediaUrls(attachments[i].ID, &attachments[i].Url, &attachments[i].ThumbnailUrl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unattachedMediaFilter matches the media of the user that no post uses yet
+func unattachedMediaFilter(userID primitive.ObjectID, mediaIDs []primitive.ObjectID) bson.M {
+	return bson.M{"_id": bson.M{"$in": mediaIDs}, "userId": userID, "postId": bson.M{"$exists": false}}
+}
+
+// findPostAttachments returns the attachments of a new post in the order the media was given
+func findPostAttachments(ctx context.Context, userID primitive.ObjectID, mediaIDs []primitive.ObjectID) ([]models.PostAttachment, error) {
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	cur, err := mediaCollection.Find(ctx, unattachedMediaFilter(userID, mediaIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	media := []models.Media{}
+
+	if err := cur.All(ctx, &media); err != nil {
+		return nil, err
+	}
+
+	if len(media) != len(mediaIDs) {
+		return nil, ErrMediaNotAttachable
+	}
+
+	mediaByID := map[primitive.ObjectID]models.Media{}
+	for _, m := range media {
+		mediaByID[m.ID] = m
+	}
+
+	attachments := []models.PostAttachment{}
+	for _, mediaID := range mediaIDs {
+		m := mediaByID[mediaID]
+
+		attachments = append(attachments, models.PostAttachment{
+			ID:           m.ID,
+			Type:         m.Type,
+			Url:          m.Url,
+			ThumbnailUrl: m.ThumbnailUrl,
+			Width:        m.Width,
+			Height:       m.Height,
+			Duration:     m.Duration,
+		})
+	}
+
+	return attachments, nil
+}
+
+// attachMedia links the media to the post, failing if another post took any of them in the meantime
+func attachMedia(ctx context.Context, userID primitive.ObjectID, mediaIDs []primitive.ObjectID, postID primitive.ObjectID) error {
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	update := bson.M{"$set": bson.M{"postId": postID, "updatedAt": time.Now()}}
+
+	result, err := mediaCollection.UpdateMany(ctx, unattachedMediaFilter(userID, mediaIDs), update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount != int64(len(mediaIDs)) {
+		return ErrMediaNotAttachable
+	}
+
+	return nil
+}
+
+// detachMedia releases the media of deleted posts, they are purged with their files later on
+func detachMedia(ctx context.Context, postIDs []primitive.ObjectID) error {
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	filter := bson.M{"postId": bson.M{"$in": postIDs}}
+	update := bson.M{"$unset": bson.M{"postId": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+
+	if _, err := mediaCollection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindMediaToPurge returns the media created before the given time that isn't attached to any post
+func FindMediaToPurge(before time.Time) ([]models.Media, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	filter := bson.M{"postId": bson.M{"$exists": false}, "createdAt": bson.M{"$lt": before}}
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(1000)
+
+	cur, err := mediaCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	media := []models.Media{}
+
+	if err := cur.All(ctx, &media); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// DeleteMedia removes the media unless a post got it attached, its files are queued for deletion in the same
+// transaction
+func DeleteMedia(mediaID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	mediaCollection := db.GetCollection(db.DB, "media")
+
+	filter := bson.M{"_id": mediaID, "postId": bson.M{"$exists": false}}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		media := models.Media{}
+
+		if err := mediaCollection.FindOneAndDelete(sessCtx, filter).Decode(&media); err != nil {
+			// A post got it attached since it was listed
+			if err == mongo.ErrNoDocuments {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		return nil, scheduleBlobDeletions(sessCtx, media.Key, media.ThumbnailKey)
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
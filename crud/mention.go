@@ -0,0 +1,112 @@
+package crud
+
+import (
+	"context"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// resolveMentions maps the @handles of a post to active users, skipping those whose mention policy
+// does not allow the author to mention them. Unresolved handles are left as plain text.
+func resolveMentions(ctx context.Context, authorID primitive.ObjectID, content string) ([]models.PostMention, error) {
+	userCollection := db.GetCollection(db.DB, "users")
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	mentions := []models.PostMention{}
+
+	matches := utils.ExtractMentions(content)
+	if len(matches) == 0 {
+		return mentions, nil
+	}
+
+	handles := []string{}
+	for _, match := range matches {
+		handles = append(handles, match.Handle)
+	}
+
+	// Users blocked by the author or blocking them cannot be mentioned
+	hiddenUserIDs, err := findHiddenUserIds(ctx, authorID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []models.User{}
+
+	filter := bson.M{"handle": bson.M{"$in": handles}, "isActive": true, "_id": bson.M{"$nin": hiddenUserIDs}}
+
+	cur, err := userCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cur.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	restrictedUserIDs := []primitive.ObjectID{}
+	for _, user := range users {
+		if user.MentionPolicy == models.MentionPolicyFollowing && user.ID != authorID {
+			restrictedUserIDs = append(restrictedUserIDs, user.ID)
+		}
+	}
+
+	// Users that only accept mentions from people they follow
+	followingAuthor := map[primitive.ObjectID]bool{}
+
+	if len(restrictedUserIDs) > 0 {
+		followerRelations := []models.FollowerRelation{}
+
+		filter := bson.M{
+			"followerId": bson.M{"$in": restrictedUserIDs},
+			"followedId": authorID,
+			"status":     acceptedFollowerRelationStatus(),
+		}
+
+		cur, err := followerRelationCollection.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cur.All(ctx, &followerRelations); err != nil {
+			return nil, err
+		}
+
+		for _, followerRelation := range followerRelations {
+			followingAuthor[followerRelation.FollowerID] = true
+		}
+	}
+
+	mentionableUserIDs := map[string]primitive.ObjectID{}
+	for _, user := range users {
+		if user.ID != authorID {
+			if user.MentionPolicy == models.MentionPolicyNobody {
+				continue
+			}
+			if user.MentionPolicy == models.MentionPolicyFollowing && !followingAuthor[user.ID] {
+				continue
+			}
+		}
+
+		mentionableUserIDs[user.Handle] = user.ID
+	}
+
+	for _, match := range matches {
+		userID, ok := mentionableUserIDs[match.Handle]
+		if !ok {
+			continue
+		}
+
+		mentions = append(mentions, models.PostMention{
+			UserID: userID,
+			Handle: match.Handle,
+			Start:  match.Start,
+			End:    match.End,
+		})
+	}
+
+	return mentions, nil
+}
@@ -0,0 +1,224 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertMessage(messageCreate models.MessageCreate) (models.MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.MessageResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	messageCreate.CreatedAt = time.Now()
+	messageCreate.UpdatedAt = time.Now()
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := messageCollection.InsertOne(sessCtx, messageCreate)
+		if err != nil {
+			return nil, err
+		}
+
+		// The sender has read their own message, everyone else has one more unread message
+		filter := bson.M{"_id": messageCreate.ConversationID}
+		update := bson.M{
+			"$set": bson.M{
+				"lastMessageAt": messageCreate.CreatedAt,
+				"updatedAt":     messageCreate.CreatedAt,
+				"participants.$[sender].lastReadMessageId": result.InsertedID,
+				"participants.$[sender].lastReadAt":        messageCreate.CreatedAt,
+			},
+			"$inc": bson.M{"participants.$[other].unreadCount": 1},
+		}
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"sender.userId": messageCreate.SenderID},
+			bson.M{"other.userId": bson.M{"$ne": messageCreate.SenderID}},
+		}})
+
+		if _, err := conversationCollection.UpdateOne(sessCtx, filter, update, opts); err != nil {
+			return nil, err
+		}
+
+		return result.InsertedID, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, opts)
+	if err != nil {
+		return models.MessageResponse{}, err
+	}
+
+	return FindOneMessageById(result.(primitive.ObjectID))
+}
+
+func findOneMessage(filter interface{}, opts ...*options.FindOneOptions) (models.MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	messageResponse := models.MessageResponse{}
+
+	if err := messageCollection.FindOne(ctx, filter, opts...).Decode(&messageResponse); err != nil {
+		return models.MessageResponse{}, err
+	}
+
+	return messageResponse, nil
+}
+
+func FindOneMessageById(messageID primitive.ObjectID) (models.MessageResponse, error) {
+	filter := bson.M{"_id": messageID}
+
+	return findOneMessage(filter)
+}
+
+func FindOneLastMessageByConversationId(conversationID primitive.ObjectID) (models.MessageResponse, error) {
+	filter := bson.M{"conversationId": conversationID}
+	opts := options.FindOne().SetSort(bson.M{"_id": -1})
+
+	return findOneMessage(filter, opts)
+}
+
+func FindAllMessagesBySenderId(senderID primitive.ObjectID) ([]models.MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	filter := bson.M{"senderId": senderID}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+
+	cur, err := messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	messagesResponse := []models.MessageResponse{}
+
+	if err := cur.All(ctx, &messagesResponse); err != nil {
+		return nil, err
+	}
+
+	return messagesResponse, nil
+}
+
+// FindAllConversationMessages returns the history of a conversation from the newest message backwards
+func FindAllConversationMessages(conversationID primitive.ObjectID, cursor primitive.ObjectID, limit int64) (models.MessagesPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	filter := bson.M{"conversationId": conversationID}
+	if !cursor.IsZero() {
+		filter["_id"] = bson.M{"$lt": cursor}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": -1}).SetLimit(limit)
+
+	cur, err := messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return models.MessagesPage{}, err
+	}
+
+	messagesPage := models.MessagesPage{Data: []models.MessageResponse{}}
+
+	if err := cur.All(ctx, &messagesPage.Data); err != nil {
+		return models.MessagesPage{}, err
+	}
+
+	if int64(len(messagesPage.Data)) == limit {
+		nextCursor := messagesPage.Data[len(messagesPage.Data)-1].ID
+		messagesPage.NextCursor = &nextCursor
+	}
+
+	return messagesPage, nil
+}
+
+func UpdateMessage(messageID primitive.ObjectID, messageUpdate models.MessageUpdate) (models.MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+
+	messageUpdate.EditedAt = time.Now()
+	messageUpdate.UpdatedAt = time.Now()
+
+	filter := bson.M{"_id": messageID}
+	update := bson.M{"$set": messageUpdate}
+
+	if _, err := messageCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.MessageResponse{}, err
+	}
+
+	return FindOneMessageById(messageID)
+}
+
+// DeleteMessage blanks a message and keeps it as a placeholder in the history, participants that
+// had not read it get one unread message less
+func DeleteMessage(messageID primitive.ObjectID, messageResponse models.MessageResponse) (models.MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.MessageResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	messageCollection := db.GetCollection(db.DB, "messages")
+	conversationCollection := db.GetCollection(db.DB, "conversations")
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"_id": messageID}
+		update := bson.M{"$set": bson.M{"content": "", "deletedAt": time.Now(), "updatedAt": time.Now()}}
+
+		if _, err := messageCollection.UpdateOne(sessCtx, filter, update); err != nil {
+			return nil, err
+		}
+
+		filter = bson.M{"_id": messageResponse.ConversationID}
+		update = bson.M{"$inc": bson.M{"participants.$[unread].unreadCount": -1}}
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{
+				"unread.userId":      bson.M{"$ne": messageResponse.SenderID},
+				"unread.unreadCount": bson.M{"$gt": 0},
+				"$or": []bson.M{
+					{"unread.lastReadMessageId": nil},
+					{"unread.lastReadMessageId": bson.M{"$lt": messageID}},
+				},
+			},
+		}})
+
+		if _, err := conversationCollection.UpdateOne(sessCtx, filter, update, opts); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return models.MessageResponse{}, err
+	}
+
+	return FindOneMessageById(messageID)
+}
@@ -0,0 +1,106 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertMute(muteCreate models.MuteCreate) (models.MuteResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	muteCollection := db.GetCollection(db.DB, "mutes")
+
+	muteCreate.CreatedAt = time.Now()
+	muteCreate.UpdatedAt = time.Now()
+
+	result, err := muteCollection.InsertOne(ctx, muteCreate)
+	if err != nil {
+		return models.MuteResponse{}, err
+	}
+
+	return FindOneMuteById(result.InsertedID.(primitive.ObjectID))
+}
+
+func findOneMute(filter interface{}, opts ...*options.FindOneOptions) (models.MuteResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	muteCollection := db.GetCollection(db.DB, "mutes")
+
+	muteResponse := models.MuteResponse{}
+
+	if err := muteCollection.FindOne(ctx, filter, opts...).Decode(&muteResponse); err != nil {
+		return models.MuteResponse{}, err
+	}
+
+	return muteResponse, nil
+}
+
+func FindOneMuteById(muteID primitive.ObjectID) (models.MuteResponse, error) {
+	filter := bson.M{"_id": muteID}
+
+	return findOneMute(filter)
+}
+
+func FindOneMuteByUserIds(muterID primitive.ObjectID, mutedID primitive.ObjectID) (models.MuteResponse, error) {
+	filter := bson.M{"muterId": muterID, "mutedId": mutedID}
+
+	return findOneMute(filter)
+}
+
+func FindAllMutedUsers(muterID primitive.ObjectID, skip int64, limit int64) ([]models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	muteCollection := db.GetCollection(db.DB, "mutes")
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"muterId": muterID}},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "mutedId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$replaceRoot": bson.M{"newRoot": "$user"}},
+	}
+
+	cur, err := muteCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	usersResponse := []models.UserResponse{}
+
+	if err := cur.All(ctx, &usersResponse); err != nil {
+		return nil, err
+	}
+
+	return usersResponse, nil
+}
+
+func DeleteMute(muteID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	muteCollection := db.GetCollection(db.DB, "mutes")
+
+	filter := bson.M{"_id": muteID}
+
+	if _, err := muteCollection.DeleteOne(ctx, filter); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notificationActorsLimit is the number of actors embedded in a grouped notification
+const notificationActorsLimit = 3
+
+// InsertNotification adds the actor to the unread notification of the same type and post, creating
+// it when there is none
+func InsertNotification(notificationCreate models.NotificationCreate) (models.NotificationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	filter := bson.M{
+		"userId": notificationCreate.UserID,
+		"type":   notificationCreate.Type,
+		"read":   false,
+	}
+	if notificationCreate.PostID.IsZero() {
+		filter["postId"] = bson.M{"$exists": false}
+	} else {
+		filter["postId"] = notificationCreate.PostID
+	}
+
+	update := bson.M{
+		"$addToSet":    bson.M{"actorIds": notificationCreate.ActorID},
+		"$set":         bson.M{"updatedAt": time.Now()},
+		"$setOnInsert": bson.M{"createdAt": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	notification := models.Notification{}
+
+	err := notificationCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification)
+	// A concurrent upsert created the group first, so the retry updates it
+	if mongo.IsDuplicateKeyError(err) {
+		err = notificationCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification)
+	}
+	if err != nil {
+		return models.NotificationResponse{}, err
+	}
+
+	return FindOneNotificationById(notification.ID)
+}
+
+func notificationResponseStages() []bson.M {
+	return []bson.M{
+		{"$addFields": bson.M{
+			"actorsCount":  bson.M{"$size": "$actorIds"},
+			"lastActorIds": bson.M{"$slice": []interface{}{"$actorIds", -notificationActorsLimit}},
+		}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "lastActorIds",
+			"foreignField": "_id",
+			"as":           "actors",
+		}},
+		{"$project": bson.M{"actorIds": 0, "lastActorIds": 0}},
+	}
+}
+
+func findNotifications(pipeline interface{}, opts ...*options.AggregateOptions) ([]models.NotificationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	cur, err := notificationCollection.Aggregate(ctx, pipeline, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationsResponse := []models.NotificationResponse{}
+
+	if err := cur.All(ctx, &notificationsResponse); err != nil {
+		return nil, err
+	}
+
+	return notificationsResponse, nil
+}
+
+func FindOneNotificationById(notificationID primitive.ObjectID) (models.NotificationResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"_id": notificationID}},
+	}
+	pipeline = append(pipeline, notificationResponseStages()...)
+
+	notificationsResponse, err := findNotifications(pipeline)
+	if err != nil {
+		return models.NotificationResponse{}, err
+	}
+
+	if len(notificationsResponse) == 0 {
+		return models.NotificationResponse{}, mongo.ErrNoDocuments
+	}
+
+	return notificationsResponse[0], nil
+}
+
+func FindAllNotificationsByUserId(userID primitive.ObjectID, unreadOnly bool, skip int64, limit int64) ([]models.NotificationResponse, error) {
+	match := bson.M{"userId": userID}
+	if unreadOnly {
+		match["read"] = false
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.M{"updatedAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, notificationResponseStages()...)
+
+	return findNotifications(pipeline)
+}
+
+func CountUnreadNotificationsByUserId(userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	filter := bson.M{"userId": userID, "read": false}
+
+	return notificationCollection.CountDocuments(ctx, filter)
+}
+
+func MarkNotificationRead(notificationID primitive.ObjectID) (models.NotificationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	filter := bson.M{"_id": notificationID}
+	update := bson.M{"$set": bson.M{"read": true}}
+
+	if _, err := notificationCollection.UpdateOne(ctx, filter, update); err != nil {
+		return models.NotificationResponse{}, err
+	}
+
+	return FindOneNotificationById(notificationID)
+}
+
+func MarkAllNotificationsRead(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	filter := bson.M{"userId": userID, "read": false}
+	update := bson.M{"$set": bson.M{"read": true}}
+
+	_, err := notificationCollection.UpdateMany(ctx, filter, update)
+
+	return err
+}
+
+func DeleteNotificationsUpdatedBefore(updatedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	filter := bson.M{"updatedAt": bson.M{"$lt": updatedAt}}
+
+	_, err := notificationCollection.DeleteMany(ctx, filter)
+
+	return err
+}
+
+// deleteUserNotifications removes the notifications of a user and takes them out of the actors of others
+func deleteUserNotifications(ctx context.Context, userID primitive.ObjectID) error {
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+
+	if _, err := notificationCollection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return err
+	}
+
+	filter := bson.M{"actorIds": userID}
+	update := bson.M{"$pull": bson.M{"actorIds": userID}}
+
+	if _, err := notificationCollection.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	if _, err := notificationCollection.DeleteMany(ctx, bson.M{"actorIds": bson.M{"$size": 0}}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,40 +2,226 @@ package crud
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/wilfredohq/fiber-start/db"
 	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrPostAlreadyPublished is returned when the status of a post that is already published is changed
+var ErrPostAlreadyPublished = errors.New("post already published")
+
+// ErrUpdatedPostNotFound is returned when a post was updated but can no longer be found, because its author was
+// deactivated for example
+var ErrUpdatedPostNotFound = errors.New("updated post not found")
+
+// postAnnouncementLease is how long an announcement may take before another instance delivers it again
+const postAnnouncementLease = 5 * time.Minute
+
 func InsertPost(postCreate models.PostCreate) (models.PostResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
 	postCollection := db.GetCollection(db.DB, "posts")
 
+	mentions, err := resolveMentions(ctx, postCreate.UserID, *postCreate.Content)
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+
+	attachments := []models.PostAttachment{}
+	if len(postCreate.MediaIDs) > 0 {
+		if attachments, err = findPostAttachments(ctx, postCreate.UserID, postCreate.MediaIDs); err != nil {
+			return models.PostResponse{}, err
+		}
+	}
+
+	if postCreate.Status == nil {
+		status := models.PostStatusPublished
+		postCreate.Status = &status
+	}
+
+	postCreate.Tags = utils.ExtractHashtags(*postCreate.Content)
+	postCreate.Mentions = mentions
+	postCreate.Attachments = attachments
 	postCreate.CreatedAt = time.Now()
 	postCreate.UpdatedAt = time.Now()
+	if *postCreate.Status == models.PostStatusPublished {
+		postCreate.AnnouncePending = true
+		postCreate.AnnounceLeaseUntil = postCreate.CreatedAt.Add(postAnnouncementLease)
+	}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		result, err := postCollection.InsertOne(sessCtx, postCreate)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(postCreate.MediaIDs) > 0 {
+			if err := attachMedia(sessCtx, postCreate.UserID, postCreate.MediaIDs, result.InsertedID.(primitive.ObjectID)); err != nil {
+				return nil, err
+			}
+		}
+
+		originalID := postCreate.RepostOfID
+		if originalID == nil {
+			originalID = postCreate.QuoteOfID
+		}
+
+		// Drafts and scheduled quotes count once published
+		if originalID != nil && *postCreate.Status == models.PostStatusPublished {
+			if err := UpdatePostRepostsCount(sessCtx, *originalID, 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return result.InsertedID, nil
+	}
 
-	result, err := postCollection.InsertOne(ctx, postCreate)
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, opts)
 	if err != nil {
 		return models.PostResponse{}, err
 	}
 
-	return FindOnePostById(result.InsertedID.(primitive.ObjectID))
+	return FindOnePostById(result.(primitive.ObjectID), postCreate.UserID)
+}
+
+// postOriginalStages embeds the reposted or quoted post, which is missing once the original is deleted or its author is hidden. Reposts are
+// deleted along with their original, so only quotes are left without one.
+func postOriginalStages(hiddenUserIDs []primitive.ObjectID) []bson.M {
+	originalMatch := bson.M{
+		"deletedAt": nil,
+		"$expr":     bson.M{"$eq": []string{"$_id", "$$originalId"}},
+	}
+	if len(hiddenUserIDs) > 0 {
+		originalMatch["userId"] = bson.M{"$nin": hiddenUserIDs}
+	}
+
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from": "posts",
+			"let":  bson.M{"originalId": bson.M{"$ifNull": []string{"$repostOfId", "$quoteOfId"}}},
+			"pipeline": []bson.M{
+				{"$match": originalMatch},
+				{"$lookup": bson.M{
+					"from":         "users",
+					"localField":   "userId",
+					"foreignField": "_id",
+					"as":           "user",
+				}},
+				{"$unwind": "$user"},
+			},
+			"as": "original",
+		}},
+		{"$unwind": bson.M{"path": "$original", "preserveNullAndEmptyArrays": true}},
+	}
+}
+
+// postViewerStages adds the fields of a post that depend on who is looking at it
+func postViewerStages(viewerID primitive.ObjectID) []bson.M {
+	return []bson.M{
+		{"$lookup": bson.M{
+			"from": "likes",
+			"let":  bson.M{"postId": "$_id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"userId": viewerID,
+					"$expr":  bson.M{"$eq": []string{"$postId", "$$postId"}},
+				}},
+				{"$limit": 1},
+			},
+			"as": "viewerLikes",
+		}},
+		{"$lookup": bson.M{
+			"from": "bookmarks",
+			"let":  bson.M{"postId": "$_id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"userId": viewerID,
+					"$expr":  bson.M{"$eq": []string{"$postId", "$$postId"}},
+				}},
+				{"$limit": 1},
+			},
+			"as": "viewerBookmarks",
+		}},
+		{"$addFields": bson.M{
+			"likedByMe":      bson.M{"$gt": []interface{}{bson.M{"$size": "$viewerLikes"}, 0}},
+			"bookmarkedByMe": bson.M{"$gt": []interface{}{bson.M{"$size": "$viewerBookmarks"}, 0}},
+		}},
+		{"$project": bson.M{"viewerLikes": 0, "viewerBookmarks": 0}},
+	}
+}
+
+// publishedPostStatus matches the published posts, those written before drafts existed have no status
+func publishedPostStatus() bson.M {
+	return bson.M{"$nin": []string{models.PostStatusDraft, models.PostStatusScheduled}}
+}
+
+func isPublishedPost(post models.Post) bool {
+	return post.Status == "" || post.Status == models.PostStatusPublished
+}
+
+// postVisibilityMatch hides the posts of private accounts from the users that don't follow them
+func postVisibilityMatch(ctx context.Context, viewerID primitive.ObjectID) (bson.M, error) {
+	followedIDs, err := findFollowedUserIds(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"$or": []bson.M{
+		{"user.isPrivate": bson.M{"$ne": true}},
+		{"userId": bson.M{"$in": append(followedIDs, viewerID)}},
+	}}, nil
 }
 
-func findOnePost(match interface{}, opts ...*options.AggregateOptions) (models.PostResponse, error) {
+// postResponseStages completes posts for the viewer, the originals of the hidden users are left out like deleted ones
+func postResponseStages(viewerID primitive.ObjectID, hiddenUserIDs []primitive.ObjectID) []bson.M {
+	stages := append(postOriginalStages(hiddenUserIDs), postViewerStages(viewerID)...)
+
+	return append(stages, bson.M{"$addFields": bson.M{
+		"status": bson.M{"$ifNull": []string{"$status", models.PostStatusPublished}},
+	}})
+}
+
+func findOnePost(match interface{}, viewerID primitive.ObjectID, opts ...*options.AggregateOptions) (models.PostResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	postCollection := db.GetCollection(db.DB, "posts")
 
+	visibilityMatch, err := postVisibilityMatch(ctx, viewerID)
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, false)
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+
 	pipeline := []bson.M{
+		// Drafts and scheduled posts are only seen by their author
+		{"$match": bson.M{
+			"deletedAt": nil,
+			"userId":    bson.M{"$nin": hiddenUserIDs},
+			"$or":       []bson.M{{"status": publishedPostStatus()}, {"userId": viewerID}},
+		}},
 		{"$lookup": bson.M{
 			"from":         "users",
 			"localField":   "userId",
@@ -44,7 +230,9 @@ func findOnePost(match interface{}, opts ...*options.AggregateOptions) (models.P
 		}},
 		{"$unwind": "$user"},
 		{"$match": match},
+		{"$match": visibilityMatch},
 	}
+	pipeline = append(pipeline, postResponseStages(viewerID, hiddenUserIDs)...)
 
 	cur, err := postCollection.Aggregate(ctx, pipeline, opts...)
 	if err != nil {
@@ -61,13 +249,23 @@ func findOnePost(match interface{}, opts ...*options.AggregateOptions) (models.P
 		return models.PostResponse{}, mongo.ErrNoDocuments
 	}
 
+	if err := signPostAttachmentUrls(&postResponse); err != nil {
+		return models.PostResponse{}, err
+	}
+
 	return postResponse, nil
 }
 
-func FindOnePostById(postID primitive.ObjectID) (models.PostResponse, error) {
+func FindOnePostById(postID primitive.ObjectID, viewerID primitive.ObjectID) (models.PostResponse, error) {
 	match := bson.M{"_id": postID}
 
-	return findOnePost(match)
+	return findOnePost(match, viewerID)
+}
+
+func FindOneRepostByUserAndPostIds(userID primitive.ObjectID, postID primitive.ObjectID) (models.PostResponse, error) {
+	match := bson.M{"userId": userID, "repostOfId": postID}
+
+	return findOnePost(match, userID)
 }
 
 func findPosts(pipeline interface{}, opts ...*options.AggregateOptions) ([]models.PostResponse, error) {
@@ -87,13 +285,40 @@ func findPosts(pipeline interface{}, opts ...*options.AggregateOptions) ([]model
 		return nil, err
 	}
 
+	for i := range postsResponse {
+		if err := signPostAttachmentUrls(&postsResponse[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	return postsResponse, nil
 }
 
-func FindAllPosts(userID primitive.ObjectID, search string, skip int64, limit int64) ([]models.PostResponse, error) {
-	match := bson.M{"content": bson.M{"$regex": search, "$options": "i"}}
+func FindAllPosts(viewerID primitive.ObjectID, userID primitive.ObjectID, search string, skip int64, limit int64) ([]models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Muted users are only hidden from the feed, not from their own profile
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, userID.IsZero())
+	if err != nil {
+		return nil, err
+	}
+
+	userFilter := bson.M{"$nin": hiddenUserIDs}
 	if !userID.IsZero() {
-		match["userId"] = userID
+		userFilter["$eq"] = userID
+	}
+
+	visibilityMatch, err := postVisibilityMatch(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	match := bson.M{
+		"content":   bson.M{"$regex": search, "$options": "i"},
+		"userId":    userFilter,
+		"deletedAt": nil,
+		"status":    publishedPostStatus(),
 	}
 
 	pipeline := []bson.M{
@@ -105,16 +330,82 @@ func FindAllPosts(userID primitive.ObjectID, search string, skip int64, limit in
 		}},
 		{"$unwind": "$user"},
 		{"$match": match},
+		{"$match": visibilityMatch},
+		{"$sort": bson.M{"createdAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+	}
+	pipeline = append(pipeline, postResponseStages(viewerID, hiddenUserIDs)...)
+
+	return findPosts(pipeline)
+}
+
+func FindAllPostsByTag(viewerID primitive.ObjectID, tag string, skip int64, limit int64) ([]models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	visibilityMatch, err := postVisibilityMatch(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"tags":      tag,
+			"userId":    bson.M{"$nin": hiddenUserIDs},
+			"deletedAt": nil,
+			"status":    publishedPostStatus(),
+		}},
 		{"$sort": bson.M{"createdAt": -1}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$match": visibilityMatch},
 		{"$skip": skip},
 		{"$limit": limit},
 	}
+	pipeline = append(pipeline, postResponseStages(viewerID, hiddenUserIDs)...)
+
+	return findPosts(pipeline)
+}
+
+func FindAllPostsByUserId(userID primitive.ObjectID) ([]models.PostResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"userId": userID, "deletedAt": nil}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$sort": bson.M{"createdAt": -1}},
+	}
+	pipeline = append(pipeline, postResponseStages(userID, nil)...)
 
 	return findPosts(pipeline)
 }
 
 func FindHomePosts(followerID primitive.ObjectID, search string, skip int64, limit int64) ([]models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, followerID, true)
+	if err != nil {
+		return nil, err
+	}
+
 	pipeline := []bson.M{
+		{"$match": bson.M{"userId": bson.M{"$nin": hiddenUserIDs}, "deletedAt": nil, "status": publishedPostStatus()}},
 		{"$lookup": bson.M{
 			"from":         "users",
 			"localField":   "userId",
@@ -129,46 +420,623 @@ func FindHomePosts(followerID primitive.ObjectID, search string, skip int64, lim
 			"as":           "followers",
 		}},
 		{"$match": bson.M{
-			"content":              bson.M{"$regex": search, "$options": "i"},
-			"followers.followerId": followerID,
+			"content": bson.M{"$regex": search, "$options": "i"},
+			"followers": bson.M{"$elemMatch": bson.M{
+				"followerId": followerID,
+				"status":     acceptedFollowerRelationStatus(),
+			}},
 		}},
 		{"$sort": bson.M{"createdAt": -1}},
 		{"$skip": skip},
 		{"$limit": limit},
 	}
+	pipeline = append(pipeline, postResponseStages(followerID, hiddenUserIDs)...)
 
 	return findPosts(pipeline)
 }
 
-func UpdatePost(postID primitive.ObjectID, postUpdate models.PostUpdate) (models.PostResponse, error) {
+// UpdatePost applies an edit of a post, the content it replaces is kept as a revision
+func UpdatePost(postID primitive.ObjectID, editorID primitive.ObjectID, postUpdate models.PostUpdate) (models.PostResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
 	postCollection := db.GetCollection(db.DB, "posts")
+	postRevisionCollection := db.GetCollection(db.DB, "postRevisions")
 
-	postUpdate.UpdatedAt = time.Now()
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		post := models.Post{}
 
-	filter := bson.M{"_id": postID}
-	update := bson.M{"$set": postUpdate}
+		if err := postCollection.FindOne(sessCtx, bson.M{"_id": postID}).Decode(&post); err != nil {
+			return nil, err
+		}
+
+		// The scheduler may have published the post since it was checked
+		if (postUpdate.Status != nil || postUpdate.PublishAt != nil) && isPublishedPost(post) {
+			return nil, ErrPostAlreadyPublished
+		}
+
+		// The callback may run again, every attempt starts from the update it was given
+		postEdit := postUpdate
+		postEdit.UpdatedAt = time.Now()
+
+		// Saving the same content is not an edit
+		if postEdit.Content != nil && *postEdit.Content == post.Content {
+			postEdit.Content = nil
+		}
+
+		update := bson.M{}
+
+		if postEdit.Content != nil {
+			mentions, err := resolveMentions(sessCtx, post.UserID, *postEdit.Content)
+			if err != nil {
+				return nil, err
+			}
+
+			tags := utils.ExtractHashtags(*postEdit.Content)
+			postEdit.Tags = &tags
+			postEdit.Mentions = &mentions
+		}
+
+		// Nobody saw the earlier versions of a draft
+		if postEdit.Content != nil && isPublishedPost(post) {
+			postEdit.EditedAt = &postEdit.UpdatedAt
+
+			postRevision := models.PostRevision{
+				PostID:    postID,
+				EditorID:  editorID,
+				Content:   post.Content,
+				Tags:      post.Tags,
+				Mentions:  post.Mentions,
+				CreatedAt: postEdit.UpdatedAt,
+			}
+
+			if _, err := postRevisionCollection.InsertOne(sessCtx, postRevision); err != nil {
+				return nil, err
+			}
+
+			update["$inc"] = bson.M{"revisionCount": 1}
+		}
+
+		// A draft is not due anymore
+		if postEdit.Status != nil && *postEdit.Status == models.PostStatusDraft {
+			postEdit.PublishAt = nil
+			update["$unset"] = bson.M{"publishAt": ""}
+		}
+
+		update["$set"] = postEdit
+
+		if _, err := postCollection.UpdateOne(sessCtx, bson.M{"_id": postID}, update); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
 
-	if _, err := postCollection.UpdateOne(ctx, filter, update); err != nil {
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
 		return models.PostResponse{}, err
 	}
 
-	return FindOnePostById(postID)
+	return FindOnePostById(postID, editorID)
+}
+
+// deletePosts removes the matching posts together with their reposts, likes, comments, notifications and
+// bookmarks and revisions, and detaches their media. Quotes of a deleted post are kept and simply lose their embedded original.
+func deletePosts(ctx context.Context, filter bson.M) error {
+	postCollection := db.GetCollection(db.DB, "posts")
+	likeCollection := db.GetCollection(db.DB, "likes")
+	commentCollection := db.GetCollection(db.DB, "comments")
+	notificationCollection := db.GetCollection(db.DB, "notifications")
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+	postRevisionCollection := db.GetCollection(db.DB, "postRevisions")
+
+	posts := []models.Post{}
+
+	cur, err := postCollection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if err := cur.All(ctx, &posts); err != nil {
+		return err
+	}
+
+	postIDs := []primitive.ObjectID{}
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	reposts := []models.Post{}
+
+	cur, err = postCollection.Find(ctx, bson.M{"repostOfId": bson.M{"$in": postIDs}, "_id": bson.M{"$nin": postIDs}})
+	if err != nil {
+		return err
+	}
+
+	if err := cur.All(ctx, &reposts); err != nil {
+		return err
+	}
+
+	deletedPostIDs := map[primitive.ObjectID]bool{}
+	for _, post := range append(posts, reposts...) {
+		deletedPostIDs[post.ID] = true
+	}
+
+	// Originals that survive lose one repost per deleted repost or quote, soft deleted and unpublished posts are not
+	// counted
+	repostsCountDeltas := map[primitive.ObjectID]int{}
+	for _, post := range posts {
+		if !post.DeletedAt.IsZero() || !isPublishedPost(post) {
+			continue
+		}
+
+		originalID := post.RepostOfID
+		if originalID.IsZero() {
+			originalID = post.QuoteOfID
+		}
+
+		if !originalID.IsZero() && !deletedPostIDs[originalID] {
+			repostsCountDeltas[originalID]--
+		}
+	}
+
+	for originalID, repostsCountDelta := range repostsCountDeltas {
+		if err := UpdatePostRepostsCount(ctx, originalID, repostsCountDelta); err != nil {
+			return err
+		}
+	}
+
+	allPostIDs := []primitive.ObjectID{}
+	for postID := range deletedPostIDs {
+		allPostIDs = append(allPostIDs, postID)
+	}
+
+	if _, err := likeCollection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	if _, err := commentCollection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	if _, err := notificationCollection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	if _, err := bookmarkCollection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	if _, err := postRevisionCollection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	if err := detachMedia(ctx, allPostIDs); err != nil {
+		return err
+	}
+
+	if _, err := postCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": allPostIDs}}); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func DeletePost(postID primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	filter := bson.M{"_id": postID}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, deletePosts(sessCtx, filter)
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAllUnpublishedPostsByUserId returns the drafts and scheduled posts of the user, last edited first
+func FindAllUnpublishedPostsByUserId(userID primitive.ObjectID, skip int64, limit int64) ([]models.PostResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"userId":    userID,
+			"deletedAt": nil,
+			"status":    bson.M{"$in": []string{models.PostStatusDraft, models.PostStatusScheduled}},
+		}},
+		{"$sort": bson.M{"updatedAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+	}
+	pipeline = append(pipeline, postResponseStages(userID, nil)...)
+
+	return findPosts(pipeline)
+}
+
+// publishPost publishes the first unpublished post matching the filter. The status only changes once, so when
+// several instances race for the same post a single one gets it back and announces it.
+func publishPost(filter bson.M, opts ...*options.FindOneAndUpdateOptions) (models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	filter["deletedAt"] = nil
+	if _, ok := filter["status"]; !ok {
+		filter["status"] = bson.M{"$in": []string{models.PostStatusDraft, models.PostStatusScheduled}}
+	}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		now := time.Now()
+
+		// Feeds are sorted by creation, a published post is new whenever it was written
+		update := bson.M{
+			"$set": bson.M{
+				"status":             models.PostStatusPublished,
+				"announcePending":    true,
+				"announceLeaseUntil": now.Add(postAnnouncementLease),
+				"createdAt":          now,
+				"updatedAt":          now,
+			},
+			"$unset": bson.M{"publishAt": ""},
+		}
+
+		post := models.Post{}
+
+		if err := postCollection.FindOneAndUpdate(sessCtx, filter, update, opts...).Decode(&post); err != nil {
+			return nil, err
+		}
+
+		if !post.QuoteOfID.IsZero() {
+			if err := UpdatePostRepostsCount(sessCtx, post.QuoteOfID, 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return post, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	txnOpts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	result, err := session.WithTransaction(ctx, transactionCallback, txnOpts)
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+
+	post := result.(models.Post)
+
+	return findUpdatedPost(post)
+}
+
+func PublishPost(postID primitive.ObjectID) (models.PostResponse, error) {
+	filter := bson.M{"_id": postID}
+
+	return publishPost(filter)
+}
+
+// PublishDuePost publishes the scheduled post that is the most overdue, mongo.ErrNoDocuments when none is due
+func PublishDuePost(now time.Time) (models.PostResponse, error) {
+	filter := bson.M{"status": models.PostStatusScheduled, "publishAt": bson.M{"$lte": now}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"publishAt": 1})
+
+	publishedPost, err := publishPost(filter, opts)
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+
+	return publishedPost, nil
+}
+
+// ClaimPendingAnnouncement returns a published post whose announcement lease expired before the given time, left
+// unfinished by an instance that stopped for example. Claiming it takes a new lease, which delays other claims.
+func ClaimPendingAnnouncement(now time.Time) (models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	// Posts left pending before leases existed have none
+	filter := bson.M{"announcePending": true, "announceLeaseUntil": bson.M{"$not": bson.M{"$gt": now}}, "deletedAt": nil}
+	update := bson.M{"$set": bson.M{"announceLeaseUntil": now.Add(postAnnouncementLease)}}
+
+	post := models.Post{}
+
+	if err := postCollection.FindOneAndUpdate(ctx, filter, update).Decode(&post); err != nil {
+		return models.PostResponse{}, err
+	}
+
+	return findUpdatedPost(post)
+}
+
+// findUpdatedPost finds a post right after it was updated, so mongo.ErrNoDocuments keeps meaning that no post matched
+// the update
+func findUpdatedPost(post models.Post) (models.PostResponse, error) {
+	postResponse, err := FindOnePostById(post.ID, post.UserID)
+	if err == mongo.ErrNoDocuments {
+		return models.PostResponse{}, fmt.Errorf("post %s: %w", post.ID.Hex(), ErrUpdatedPostNotFound)
+	}
+
+	return postResponse, err
+}
+
+func MarkPostAnnounced(postID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"announcePending": "", "announceLeaseUntil": ""}}
+
+	return updatePostCustomFields(ctx, postID, update)
+}
+
+// SoftDeletePost hides a post, with its reposts, until it is restored or purged. A deleted quote no longer counts
+// as a repost of its original.
+func SoftDeletePost(postID primitive.ObjectID, deletedBy primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		post := models.Post{}
+
+		if err := postCollection.FindOne(sessCtx, bson.M{"_id": postID, "deletedAt": nil}).Decode(&post); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+
+		update := bson.M{"$set": bson.M{"deletedAt": now, "deletedBy": deletedBy}}
+
+		if _, err := postCollection.UpdateOne(sessCtx, bson.M{"_id": postID}, update); err != nil {
+			return nil, err
+		}
+
+		repostsFilter := bson.M{"repostOfId": postID, "deletedAt": nil}
+		repostsUpdate := bson.M{"$set": bson.M{"deletedAt": now, "deletedBy": deletedBy, "deletedWithId": postID}}
+
+		if _, err := postCollection.UpdateMany(sessCtx, repostsFilter, repostsUpdate); err != nil {
+			return nil, err
+		}
+
+		if !post.QuoteOfID.IsZero() && isPublishedPost(post) {
+			if err := UpdatePostRepostsCount(sessCtx, post.QuoteOfID, -1); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindOneDeletedPostById returns a soft deleted post, reposts deleted along with their original are left out
+func FindOneDeletedPostById(postID primitive.ObjectID) (models.Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	filter := bson.M{"_id": postID, "deletedAt": bson.M{"$ne": nil}, "deletedWithId": nil}
+
+	post := models.Post{}
+
+	if err := postCollection.FindOne(ctx, filter).Decode(&post); err != nil {
+		return models.Post{}, err
+	}
+
+	return post, nil
+}
+
+func FindAllDeletedPostsByUserId(viewerID primitive.ObjectID, userID primitive.ObjectID, skip int64, limit int64) ([]models.PostResponse, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"userId": userID, "deletedAt": bson.M{"$ne": nil}, "deletedWithId": nil}},
+		{"$sort": bson.M{"deletedAt": -1}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+	}
+	pipeline = append(pipeline, postResponseStages(viewerID, nil)...)
+
+	return findPosts(pipeline)
+}
+
+// RestorePost brings back a soft deleted post together with the reposts deleted along with it
+func RestorePost(postID primitive.ObjectID, viewerID primitive.ObjectID) (models.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.PostResponse{}, err
+	}
+	defer session.EndSession(ctx)
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		post := models.Post{}
+
+		filter := bson.M{"_id": postID, "deletedAt": bson.M{"$ne": nil}}
+		update := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}
+
+		if err := postCollection.FindOneAndUpdate(sessCtx, filter, update).Decode(&post); err != nil {
+			return nil, err
+		}
+
+		repostsFilter := bson.M{"deletedWithId": postID}
+		repostsUpdate := bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": "", "deletedWithId": ""}}
+
+		if _, err := postCollection.UpdateMany(sessCtx, repostsFilter, repostsUpdate); err != nil {
+			return nil, err
+		}
+
+		if !post.QuoteOfID.IsZero() && isPublishedPost(post) {
+			if err := UpdatePostRepostsCount(sessCtx, post.QuoteOfID, 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return models.PostResponse{}, err
+	}
+
+	return FindOnePostById(postID, viewerID)
+}
+
+// PurgeDeletedPosts removes for good the posts soft deleted before the given time
+func PurgeDeletedPosts(deletedBefore time.Time) error {
+	for {
+		purgedCount, err := purgeDeletedPostsBatch(deletedBefore, 100)
+		if err != nil {
+			return err
+		}
+
+		if purgedCount == 0 {
+			return nil
+		}
+	}
+}
+
+// purgeDeletedPostsBatch keeps every transaction small, a purge may cover many posts
+func purgeDeletedPostsBatch(deletedBefore time.Time, limit int64) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(limit)
+
+	cur, err := postCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	posts := []models.Post{}
+
+	if err := cur.All(ctx, &posts); err != nil {
+		return 0, err
+	}
+
+	if len(posts) == 0 {
+		return 0, nil
+	}
+
+	postIDs := []primitive.ObjectID{}
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, deletePosts(sessCtx, bson.M{"_id": bson.M{"$in": postIDs}})
+	}
+
+	maxCommitTime := 10 * time.Second
+	txnOpts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, txnOpts); err != nil {
+		return 0, err
+	}
+
+	return len(posts), nil
+}
+
+func updatePostCustomFields(ctx context.Context, postID primitive.ObjectID, update interface{}, opts ...*options.UpdateOptions) error {
 	postCollection := db.GetCollection(db.DB, "posts")
 
 	filter := bson.M{"_id": postID}
 
-	if _, err := postCollection.DeleteOne(ctx, filter); err != nil {
+	if _, err := postCollection.UpdateOne(ctx, filter, update, opts...); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func UpdatePostLikesCount(ctx context.Context, postID primitive.ObjectID, likesCountDelta int) error {
+	update := bson.M{"$inc": bson.M{"likesCount": likesCountDelta}}
+
+	return updatePostCustomFields(ctx, postID, update)
+}
+
+func UpdatePostCommentsCount(ctx context.Context, postID primitive.ObjectID, commentsCountDelta int) error {
+	update := bson.M{"$inc": bson.M{"commentsCount": commentsCountDelta}}
+
+	return updatePostCustomFields(ctx, postID, update)
+}
+
+func UpdatePostRepostsCount(ctx context.Context, postID primitive.ObjectID, repostsCountDelta int) error {
+	update := bson.M{"$inc": bson.M{"repostsCount": repostsCountDelta}}
+
+	return updatePostCustomFields(ctx, postID, update)
+}
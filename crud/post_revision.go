@@ -0,0 +1,35 @@
+package crud
+
+import (
+	"context"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func FindAllPostRevisionsByPostId(postID primitive.ObjectID, skip int64, limit int64) ([]models.PostRevisionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	postRevisionCollection := db.GetCollection(db.DB, "postRevisions")
+
+	filter := bson.M{"postId": postID}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1}).SetSkip(skip).SetLimit(limit)
+
+	cur, err := postRevisionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	postRevisionsResponse := []models.PostRevisionResponse{}
+
+	if err := cur.All(ctx, &postRevisionsResponse); err != nil {
+		return nil, err
+	}
+
+	return postRevisionsResponse, nil
+}
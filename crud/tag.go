@@ -0,0 +1,62 @@
+package crud
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FindTrendingTags ranks the tags used during the window ending now. Every use is weighted by
+// exponential decay with a half-life of a quarter of the window, so recent posts count more.
+func FindTrendingTags(window time.Duration, limit int64) ([]models.TrendingTag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	postCollection := db.GetCollection(db.DB, "posts")
+
+	now := time.Now()
+	halfLifeMilliseconds := float64(window.Milliseconds()) / 4
+	decayRate := -math.Ln2 / halfLifeMilliseconds
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"createdAt": bson.M{"$gte": now.Add(-window)},
+			"tags.0":    bson.M{"$exists": true},
+			"deletedAt": nil,
+			"status":    publishedPostStatus(),
+		}},
+		{"$project": bson.M{
+			"tags": 1,
+			"weight": bson.M{"$exp": bson.M{"$multiply": []interface{}{
+				decayRate,
+				bson.M{"$subtract": []interface{}{now, "$createdAt"}},
+			}}},
+		}},
+		{"$unwind": "$tags"},
+		{"$group": bson.M{
+			"_id":        "$tags",
+			"score":      bson.M{"$sum": "$weight"},
+			"postsCount": bson.M{"$sum": 1},
+		}},
+		{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": limit},
+		{"$project": bson.M{"_id": 0, "tag": "$_id", "score": 1, "postsCount": 1}},
+	}
+
+	cur, err := postCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	trendingTags := []models.TrendingTag{}
+
+	if err := cur.All(ctx, &trendingTags); err != nil {
+		return nil, err
+	}
+
+	return trendingTags, nil
+}
@@ -2,6 +2,7 @@ package crud
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/wilfredohq/fiber-start/db"
@@ -9,9 +10,20 @@ import (
 	"github.com/wilfredohq/fiber-start/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Suggestions rank users by their posts over the last days, capped so prolific users don't outweigh the social graph.
+// The candidates are the users two hops away in the follow graph and the most followed users, both capped so the
+// ranking never scans the whole collection.
+const (
+	userSuggestionActivityDays         = 30
+	userSuggestionMaxRecentPosts       = 5
+	userSuggestionMaxGraphCandidates   = 500
+	userSuggestionMaxPopularCandidates = 100
+)
+
 func InsertUser(userCreate models.UserCreate) (models.UserResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -62,6 +74,12 @@ func FindOneUserByEmail(email string) (models.UserResponse, error) {
 	return findOneUser(filter)
 }
 
+func FindOneUserByHandle(handle string) (models.UserResponse, error) {
+	filter := bson.M{"handle": handle}
+
+	return findOneUser(filter)
+}
+
 func findUsers(pipeline interface{}, opts ...*options.AggregateOptions) ([]models.UserResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -82,13 +100,24 @@ func findUsers(pipeline interface{}, opts ...*options.AggregateOptions) ([]model
 	return usersResponse, nil
 }
 
-func FindAllUsers(followerID primitive.ObjectID, followedID primitive.ObjectID, search string, skip int64, limit int64) ([]models.UserResponse, error) {
-	match := bson.M{"fullName": bson.M{"$regex": search, "$options": "i"}}
+func FindAllUsers(viewerID primitive.ObjectID, followerID primitive.ObjectID, followedID primitive.ObjectID, search string, skip int64, limit int64) ([]models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	match := bson.M{
+		"_id":      bson.M{"$nin": hiddenUserIDs},
+		"fullName": bson.M{"$regex": search, "$options": "i"},
+	}
 	if !followerID.IsZero() {
-		match["followers.followerId"] = followerID
+		match["followers"] = bson.M{"$elemMatch": bson.M{"followerId": followerID, "status": acceptedFollowerRelationStatus()}}
 	}
 	if !followedID.IsZero() {
-		match["following.followedId"] = followedID
+		match["following"] = bson.M{"$elemMatch": bson.M{"followedId": followedID, "status": acceptedFollowerRelationStatus()}}
 	}
 	pipeline := []bson.M{
 		{"$lookup": bson.M{
@@ -112,32 +141,526 @@ func FindAllUsers(followerID primitive.ObjectID, followedID primitive.ObjectID,
 	return findUsers(pipeline)
 }
 
-func UpdateUser(userID primitive.ObjectID, userUpdate models.UserUpdate) (models.UserResponse, error) {
+// FindUserSuggestions ranks the users the viewer might want to follow, by how many of the followed users follow
+// them, how many followers they share with the viewer and how active they were lately
+func FindUserSuggestions(viewerID primitive.ObjectID, skip int64, limit int64) ([]models.UserSuggestionResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	userCollection := db.GetCollection(db.DB, "users")
+	hiddenUserIDs, err := findHiddenUserIds(ctx, viewerID, true)
+	if err != nil {
+		return nil, err
+	}
 
-	if userUpdate.Password != nil {
-		hashedPassword, err := utils.GetPasswordHash(*userUpdate.Password)
-		if err != nil {
-			return models.UserResponse{}, err
+	followingRelations, err := findFollowerRelations(ctx, bson.M{"followerId": viewerID})
+	if err != nil {
+		return nil, err
+	}
+
+	followerIDs, err := findFollowerUserIds(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending follow requests are left out as well
+	excludedUserIDs := append(hiddenUserIDs, viewerID)
+	followedIDs := []primitive.ObjectID{}
+	for _, followerRelation := range followingRelations {
+		excludedUserIDs = append(excludedUserIDs, followerRelation.FollowedID)
+		if followerRelation.Status != models.FollowerRelationStatusPending {
+			followedIDs = append(followedIDs, followerRelation.FollowedID)
 		}
+	}
+
+	knownUserIDs := append(append([]primitive.ObjectID{}, followedIDs...), followerIDs...)
+	recentPostsSince := time.Now().AddDate(0, 0, -userSuggestionActivityDays)
+
+	// Candidates come from the relations of the followed users and the followers of the viewer, topped up with the most
+	// followed users for viewers with a small graph
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"followerId": bson.M{"$in": knownUserIDs},
+			"followedId": bson.M{"$nin": excludedUserIDs},
+			"status":     acceptedFollowerRelationStatus(),
+		}},
+		{"$group": bson.M{
+			"_id":            "$followedId",
+			"knownFollowers": bson.M{"$addToSet": bson.M{"followerId": "$followerId"}},
+		}},
+		{"$addFields": bson.M{"knownFollowersCount": bson.M{"$size": "$knownFollowers"}}},
+		{"$sort": bson.D{{Key: "knownFollowersCount", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": userSuggestionMaxGraphCandidates},
+		{"$unionWith": bson.M{
+			"coll": "users",
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"_id":                 bson.M{"$nin": excludedUserIDs},
+					"isActive":            true,
+					"deletionScheduledAt": nil,
+				}},
+				{"$sort": bson.D{{Key: "followersCount", Value: -1}, {Key: "_id", Value: 1}}},
+				{"$limit": userSuggestionMaxPopularCandidates},
+				{"$project": bson.M{"knownFollowers": bson.A{}}},
+			},
+		}},
+		{"$group": bson.M{
+			"_id":            "$_id",
+			"knownFollowers": bson.M{"$push": "$knownFollowers"},
+		}},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$match": bson.M{
+			"user.isActive":            true,
+			"user.deletionScheduledAt": nil,
+		}},
+		{"$replaceWith": bson.M{"$mergeObjects": []interface{}{
+			"$user",
+			bson.M{"knownFollowers": bson.M{"$reduce": bson.M{
+				"input":        "$knownFollowers",
+				"initialValue": bson.A{},
+				"in":           bson.M{"$concatArrays": []string{"$$value", "$$this"}},
+			}}},
+		}}},
+		{"$lookup": bson.M{
+			"from": "posts",
+			"let":  bson.M{"userId": "$_id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{
+					"createdAt": bson.M{"$gte": recentPostsSince},
+					"deletedAt": nil,
+					"status":    publishedPostStatus(),
+					"$expr":     bson.M{"$eq": []string{"$userId", "$$userId"}},
+				}},
+				{"$limit": userSuggestionMaxRecentPosts},
+				{"$count": "count"},
+			},
+			"as": "recentPosts",
+		}},
+		{"$addFields": bson.M{
+			"followedByIds": bson.M{"$map": bson.M{
+				"input": bson.M{"$filter": bson.M{
+					"input": "$knownFollowers",
+					"cond":  bson.M{"$in": []interface{}{"$$this.followerId", followedIDs}},
+				}},
+				"in": "$$this.followerId",
+			}},
+			"sharedFollowersCount": bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$knownFollowers",
+				"cond":  bson.M{"$in": []interface{}{"$$this.followerId", followerIDs}},
+			}}},
+			"recentPostsCount": bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$recentPosts.count", 0}}, 0}},
+		}},
+		{"$addFields": bson.M{
+			"followedByCount": bson.M{"$size": "$followedByIds"},
+		}},
+		{"$addFields": bson.M{
+			"score": bson.M{"$add": []interface{}{
+				bson.M{"$multiply": []interface{}{"$followedByCount", 3}},
+				bson.M{"$multiply": []interface{}{"$sharedFollowersCount", 2}},
+				"$recentPostsCount",
+			}},
+			"reason": bson.M{"$switch": bson.M{
+				"branches": []bson.M{
+					{"case": bson.M{"$gt": []interface{}{"$followedByCount", 0}}, "then": models.UserSuggestionReasonFollowedBy},
+					{"case": bson.M{"$gt": []interface{}{"$sharedFollowersCount", 0}}, "then": models.UserSuggestionReasonSharedFollowers},
+					{"case": bson.M{"$gt": []interface{}{"$recentPostsCount", 0}}, "then": models.UserSuggestionReasonActive},
+				},
+				"default": models.UserSuggestionReasonPopular,
+			}},
+		}},
+		{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "followersCount", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$skip": skip},
+		{"$limit": limit},
+		{"$lookup": bson.M{
+			"from": "users",
+			"let":  bson.M{"followedByIds": "$followedByIds"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$in": []string{"$_id", "$$followedByIds"}}}},
+				{"$sort": bson.M{"followersCount": -1}},
+				{"$limit": 2},
+			},
+			"as": "followedBy",
+		}},
+	}
+
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+
+	cur, err := followerRelationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	userSuggestionsResponse := []models.UserSuggestionResponse{}
+
+	if err := cur.All(ctx, &userSuggestionsResponse); err != nil {
+		return nil, err
+	}
+
+	return userSuggestionsResponse, nil
+}
+
+// UpdateUser updates the user and accepts their pending follow requests in the same transaction when the account
+// stops being private
+func UpdateUser(userID primitive.ObjectID, userUpdate models.UserUpdate) (models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-		userUpdate.Password = &hashedPassword
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return models.UserResponse{}, err
 	}
+	defer session.EndSession(ctx)
+
+	userCollection := db.GetCollection(db.DB, "users")
+
+	// Passwords are set through UpdateUserPassword, which revokes the sessions
 	userUpdate.UpdatedAt = time.Now()
 
 	filter := bson.M{"_id": userID}
 	update := bson.M{"$set": userUpdate}
 
-	if _, err := userCollection.UpdateOne(ctx, filter, update); err != nil {
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		previousUser := models.User{}
+
+		if err := userCollection.FindOneAndUpdate(sessCtx, filter, update).Decode(&previousUser); err != nil {
+			return nil, err
+		}
+
+		// Pending follow requests are accepted when the account becomes public
+		if previousUser.IsPrivate && userUpdate.IsPrivate != nil && !*userUpdate.IsPrivate {
+			return nil, acceptAllFollowRequests(sessCtx, userID)
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return FindOneUserById(userID)
+}
+
+func UpdateUserPassword(userID primitive.ObjectID, password string, sessionsRevokedAt time.Time) (models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hashedPassword, err := utils.GetPasswordHash(password)
+	if err != nil {
+		return models.UserResponse{}, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"password":          hashedPassword,
+		"sessionsRevokedAt": sessionsRevokedAt,
+		"updatedAt":         time.Now(),
+	}}
+
+	if err := updateUserCustomFields(ctx, userID, update); err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return FindOneUserById(userID)
+}
+
+func UpdateUserEmail(userID primitive.ObjectID, email string) (models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"email":     email,
+		"updatedAt": time.Now(),
+	}}
+
+	if err := updateUserCustomFields(ctx, userID, update); err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return FindOneUserById(userID)
+}
+
+// UpdateUserProfileImage replaces the avatar or the cover of the user, an empty key removes it. The file that is
+// replaced is queued for deletion.
+func UpdateUserProfileImage(userID primitive.ObjectID, imageType string, key string, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	userCollection := db.GetCollection(db.DB, "users")
+
+	keyField, urlField := "avatarKey", "avatarUrl"
+	if imageType == models.ProfileImageTypeCover {
+		keyField, urlField = "coverKey", "coverUrl"
+	}
+
+	update := bson.M{"$set": bson.M{keyField: key, urlField: url, "updatedAt": time.Now()}}
+	if key == "" {
+		update = bson.M{"$set": bson.M{urlField: "", "updatedAt": time.Now()}, "$unset": bson.M{keyField: ""}}
+	}
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		previousUser := models.User{}
+
+		if err := userCollection.FindOneAndUpdate(sessCtx, bson.M{"_id": userID}, update).Decode(&previousUser); err != nil {
+			return nil, err
+		}
+
+		previousKey := previousUser.AvatarKey
+		if imageType == models.ProfileImageTypeCover {
+			previousKey = previousUser.CoverKey
+		}
+
+		return nil, scheduleBlobDeletions(sessCtx, previousKey)
+	}
+
+	maxCommitTime := 10 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ScheduleUserDeletion(userID primitive.ObjectID, deletionScheduledAt time.Time) (models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"deletionScheduledAt": deletionScheduledAt,
+		"updatedAt":           time.Now(),
+	}}
+
+	if err := updateUserCustomFields(ctx, userID, update); err != nil {
 		return models.UserResponse{}, err
 	}
 
 	return FindOneUserById(userID)
 }
 
+func CancelUserDeletion(userID primitive.ObjectID) (models.UserResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$unset": bson.M{"deletionScheduledAt": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}
+
+	if err := updateUserCustomFields(ctx, userID, update); err != nil {
+		return models.UserResponse{}, err
+	}
+
+	return FindOneUserById(userID)
+}
+
+func FindUserIdsDueForDeletion(now time.Time) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userCollection := db.GetCollection(db.DB, "users")
+
+	filter := bson.M{"deletionScheduledAt": bson.M{"$exists": true, "$lte": now}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+	cur, err := userCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}{}
+
+	if err := cur.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	userIDs := []primitive.ObjectID{}
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	return userIDs, nil
+}
+
+func DeleteUser(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	userCollection := db.GetCollection(db.DB, "users")
+	postCollection := db.GetCollection(db.DB, "posts")
+	followerRelationCollection := db.GetCollection(db.DB, "followerRelations")
+	likeCollection := db.GetCollection(db.DB, "likes")
+	commentCollection := db.GetCollection(db.DB, "comments")
+	blockCollection := db.GetCollection(db.DB, "blocks")
+	muteCollection := db.GetCollection(db.DB, "mutes")
+	bookmarkCollection := db.GetCollection(db.DB, "bookmarks")
+	bookmarkCollectionCollection := db.GetCollection(db.DB, "bookmarkCollections")
+
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		followingRelations, err := findFollowerRelations(sessCtx, bson.M{"followerId": userID, "status": acceptedFollowerRelationStatus()})
+		if err != nil {
+			return nil, err
+		}
+
+		followerRelations, err := findFollowerRelations(sessCtx, bson.M{"followedId": userID, "status": acceptedFollowerRelationStatus()})
+		if err != nil {
+			return nil, err
+		}
+
+		followedIDs := []primitive.ObjectID{}
+		for _, followerRelation := range followingRelations {
+			followedIDs = append(followedIDs, followerRelation.FollowedID)
+		}
+
+		followerIDs := []primitive.ObjectID{}
+		for _, followerRelation := range followerRelations {
+			followerIDs = append(followerIDs, followerRelation.FollowerID)
+		}
+
+		if len(followedIDs) > 0 {
+			filter := bson.M{"_id": bson.M{"$in": followedIDs}}
+			update := bson.M{"$inc": bson.M{"followersCount": -1}}
+
+			if _, err := userCollection.UpdateMany(sessCtx, filter, update); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(followerIDs) > 0 {
+			filter := bson.M{"_id": bson.M{"$in": followerIDs}}
+			update := bson.M{"$inc": bson.M{"followingCount": -1}}
+
+			if _, err := userCollection.UpdateMany(sessCtx, filter, update); err != nil {
+				return nil, err
+			}
+		}
+
+		relationFilter := bson.M{"$or": []bson.M{{"followerId": userID}, {"followedId": userID}}}
+		if _, err := followerRelationCollection.DeleteMany(sessCtx, relationFilter); err != nil {
+			return nil, err
+		}
+
+		likes := []models.LikeResponse{}
+
+		cur, err := likeCollection.Find(sessCtx, bson.M{"userId": userID})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cur.All(sessCtx, &likes); err != nil {
+			return nil, err
+		}
+
+		likedPostIDs := []primitive.ObjectID{}
+		for _, like := range likes {
+			likedPostIDs = append(likedPostIDs, like.PostID)
+		}
+
+		if len(likedPostIDs) > 0 {
+			filter := bson.M{"_id": bson.M{"$in": likedPostIDs}}
+			update := bson.M{"$inc": bson.M{"likesCount": -1}}
+
+			if _, err := postCollection.UpdateMany(sessCtx, filter, update); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := likeCollection.DeleteMany(sessCtx, bson.M{"userId": userID}); err != nil {
+			return nil, err
+		}
+
+		comments := []models.CommentResponse{}
+
+		cur, err = commentCollection.Find(sessCtx, bson.M{"userId": userID})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cur.All(sessCtx, &comments); err != nil {
+			return nil, err
+		}
+
+		for _, comment := range comments {
+			if err := deleteCommentTree(sessCtx, comment.ID, comment.PostID, comment.ParentID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := deletePosts(sessCtx, bson.M{"userId": userID}); err != nil {
+			return nil, err
+		}
+
+		if err := deleteUserNotifications(sessCtx, userID); err != nil {
+			return nil, err
+		}
+
+		if err := deleteUserConversations(sessCtx, userID); err != nil {
+			return nil, err
+		}
+
+		blockFilter := bson.M{"$or": []bson.M{{"blockerId": userID}, {"blockedId": userID}}}
+		if _, err := blockCollection.DeleteMany(sessCtx, blockFilter); err != nil {
+			return nil, err
+		}
+
+		muteFilter := bson.M{"$or": []bson.M{{"muterId": userID}, {"mutedId": userID}}}
+		if _, err := muteCollection.DeleteMany(sessCtx, muteFilter); err != nil {
+			return nil, err
+		}
+
+		if _, err := bookmarkCollection.DeleteMany(sessCtx, bson.M{"userId": userID}); err != nil {
+			return nil, err
+		}
+
+		if _, err := bookmarkCollectionCollection.DeleteMany(sessCtx, bson.M{"userId": userID}); err != nil {
+			return nil, err
+		}
+
+		deletedUser := models.User{}
+
+		if err := userCollection.FindOneAndDelete(sessCtx, bson.M{"_id": userID}).Decode(&deletedUser); err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+
+		if err := scheduleBlobDeletions(sessCtx, deletedUser.AvatarKey, deletedUser.CoverKey); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	maxCommitTime := 30 * time.Second
+	opts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	if _, err := session.WithTransaction(ctx, transactionCallback, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func updateUserCustomFields(ctx context.Context, userID primitive.ObjectID, update interface{}, opts ...*options.UpdateOptions) error {
 	userCollection := db.GetCollection(db.DB, "users")
 
@@ -180,22 +703,41 @@ func AuthenticateUser(email string, password string) (models.UserResponse, error
 		return models.UserResponse{}, err
 	}
 
+	// Upgrade legacy or outdated hashes now that the plain password is known
+	if utils.PasswordNeedsRehash(dbUser.Password) {
+		// The login itself succeeded, a failed upgrade is tried again on the next one
+		hashedPassword, err := utils.GetPasswordHash(password)
+		if err == nil {
+			update := bson.M{"$set": bson.M{"password": hashedPassword}}
+
+			err = updateUserCustomFields(ctx, dbUser.ID, update)
+		}
+		if err != nil {
+			log.Printf("rehash password of user %s: %v", dbUser.ID.Hex(), err)
+		}
+	}
+
 	userResponse := models.UserResponse{
-		ID:             dbUser.ID,
-		FullName:       dbUser.FullName,
-		Biography:      dbUser.Biography,
-		Location:       dbUser.Location,
-		Birthdate:      dbUser.Birthdate,
-		Gender:         dbUser.Gender,
-		AvatarUrl:      dbUser.AvatarUrl,
-		CoverUrl:       dbUser.CoverUrl,
-		Email:          dbUser.Email,
-		IsActive:       dbUser.IsActive,
-		IsSuperuser:    dbUser.IsSuperuser,
-		CreatedAt:      dbUser.CreatedAt,
-		UpdatedAt:      dbUser.UpdatedAt,
-		FollowersCount: dbUser.FollowersCount,
-		FollowingCount: dbUser.FollowingCount,
+		ID:                  dbUser.ID,
+		FullName:            dbUser.FullName,
+		Handle:              dbUser.Handle,
+		Biography:           dbUser.Biography,
+		Location:            dbUser.Location,
+		Birthdate:           dbUser.Birthdate,
+		Gender:              dbUser.Gender,
+		AvatarUrl:           dbUser.AvatarUrl,
+		CoverUrl:            dbUser.CoverUrl,
+		Email:               dbUser.Email,
+		IsActive:            dbUser.IsActive,
+		IsSuperuser:         dbUser.IsSuperuser,
+		IsPrivate:           dbUser.IsPrivate,
+		CreatedAt:           dbUser.CreatedAt,
+		UpdatedAt:           dbUser.UpdatedAt,
+		FollowersCount:      dbUser.FollowersCount,
+		FollowingCount:      dbUser.FollowingCount,
+		SessionsRevokedAt:   dbUser.SessionsRevokedAt,
+		DeletionScheduledAt: dbUser.DeletionScheduledAt,
+		MentionPolicy:       dbUser.MentionPolicy,
 	}
 
 	return userResponse, nil
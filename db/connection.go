@@ -9,6 +9,7 @@ import (
 
 	"github.com/wilfredohq/fiber-start/config"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -17,6 +18,12 @@ func GetCollection(client *mongo.Client, name string) *mongo.Collection {
 	return collection
 }
 
+func GetBucket(client *mongo.Client, name string) (*gridfs.Bucket, error) {
+	opts := options.GridFSBucket().SetName(name)
+
+	return gridfs.NewBucket(client.Database(config.Config.DBName), opts)
+}
+
 func connectDB() *mongo.Client {
 	uri := fmt.Sprintf("mongodb+srv://%s:%s@%s/?retryWrites=true&w=majority",
 		url.QueryEscape(config.Config.DBUser),
@@ -40,6 +47,11 @@ func connectDB() *mongo.Client {
 		log.Fatal(err)
 	}
 
+	// The data breaking an index is cleaned up by the commands, which have to start to do so
+	if err := createIndexes(ctx, client); err != nil {
+		log.Print(err)
+	}
+
 	if err := createSuperuser(ctx, client); err != nil {
 		log.Fatal(err)
 	}
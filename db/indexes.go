@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateIndexes creates the indexes missing from the database, those that existing data kept from being built at
+// startup for example
+func CreateIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return createIndexes(ctx, DB)
+}
+
+// createIndexes creates every index on its own, so an index that existing data keeps from being built, a unique one
+// over duplicates for example, doesn't keep the others from being built
+func createIndexes(ctx context.Context, client *mongo.Client) error {
+	indexes := map[string][]mongo.IndexModel{
+		"blocks": {
+			{Keys: bson.D{{Key: "blockerId", Value: 1}, {Key: "blockedId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.M{"blockedId": 1}},
+		},
+		"bookmarkCollections": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"bookmarks": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "collectionId", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.M{"postId": 1}},
+		},
+		"comments": {
+			{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "parentId", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.M{"ancestorIds": 1}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		},
+		"dataExports": {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updatedAt", Value: 1}}},
+		},
+		"conversations": {
+			{
+				Keys: bson.M{"directKey": 1},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"directKey": bson.M{"$exists": true},
+				}),
+			},
+			{Keys: bson.D{{Key: "participants.userId", Value: 1}, {Key: "lastMessageAt", Value: -1}}},
+		},
+		"events": {
+			{
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "sequence", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"sequence": bson.M{"$exists": true},
+				}),
+			},
+			{Keys: bson.M{"createdAt": 1}},
+		},
+		"followerRelations": {
+			{Keys: bson.D{{Key: "followerId", Value: 1}, {Key: "followedId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "followedId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "followerId", Value: 1}, {Key: "status", Value: 1}}},
+		},
+		"likes": {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		},
+		"posts": {
+			{
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "repostOfId", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"repostOfId": bson.M{"$exists": true},
+				}),
+			},
+			{Keys: bson.M{"quoteOfId": 1}},
+			{Keys: bson.M{"deletedAt": 1}, Options: options.Index().SetSparse(true)},
+			{Keys: bson.M{"deletedWithId": 1}, Options: options.Index().SetSparse(true)},
+			{
+				Keys: bson.D{{Key: "publishAt", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{
+					"status": "scheduled",
+				}),
+			},
+			{
+				Keys: bson.D{{Key: "announceLeaseUntil", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.M{
+					"announcePending": true,
+				}),
+			},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}, {Key: "updatedAt", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.M{"createdAt": -1}},
+		},
+		"users": {
+			{
+				Keys: bson.M{"handle": 1},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"handle": bson.M{"$exists": true},
+				}),
+			},
+			{Keys: bson.D{{Key: "followersCount", Value: -1}, {Key: "_id", Value: 1}}},
+		},
+		"messages": {
+			{Keys: bson.D{{Key: "conversationId", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.M{"senderId": 1}},
+		},
+		"mutes": {
+			{Keys: bson.D{{Key: "muterId", Value: 1}, {Key: "mutedId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		"notifications": {
+			{
+				Keys: bson.D{{Key: "userId", Value: 1}, {Key: "type", Value: 1}, {Key: "postId", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+					"read": false,
+				}),
+			},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "updatedAt", Value: -1}}},
+			{Keys: bson.M{"actorIds": 1}},
+			{Keys: bson.M{"updatedAt": 1}},
+		},
+		"media": {
+			{Keys: bson.M{"postId": 1}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}}},
+			{Keys: bson.M{"createdAt": 1}},
+		},
+		"postRevisions": {
+			{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		},
+		"deletedBlobs": {
+			{Keys: bson.M{"createdAt": 1}},
+		},
+		"magicLinks": {
+			{Keys: bson.M{"tokenHash": 1}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.M{"expiresAt": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	}
+
+	var errs []error
+	for name, models := range indexes {
+		for _, model := range models {
+			if _, err := GetCollection(client, name).Indexes().CreateOne(ctx, model); err != nil {
+				errs = append(errs, fmt.Errorf("create %s index %v: %w", name, model.Keys, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Broker carries events between the instances of the server. Subscribe returns the events published
+// for the user after lastEventID, starting with a reset event when some of them are no longer kept.
+type Broker interface {
+	Publish(event models.Event) error
+	Subscribe(userID primitive.ObjectID, lastEventID string) (*Subscription, []models.Event, error)
+}
+
+func newBroker() Broker {
+	replayWindow := time.Duration(config.Config.EventsReplayMinutes) * time.Minute
+
+	switch config.Config.EventsBroker {
+	case "mongo":
+		return newMongoBroker(config.Config.EventsBufferSize, replayWindow)
+	default:
+		return newMemoryBroker(config.Config.EventsBufferSize, replayWindow)
+	}
+}
+
+var DefaultBroker = newBroker()
+
+// Publish sends an event with the JSON encoding of data to each user
+func Publish(userIDs []primitive.ObjectID, eventType string, data interface{}) {
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("events: %v", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		event := models.Event{
+			UserID:    userID,
+			Type:      eventType,
+			Data:      encodedData,
+			CreatedAt: time.Now(),
+		}
+
+		if err := DefaultBroker.Publish(event); err != nil {
+			log.Printf("events: %v", err)
+		}
+	}
+}
+
+func resetEvent(userID primitive.ObjectID) models.Event {
+	return models.Event{
+		UserID:    userID,
+		Type:      models.EventTypeReset,
+		Data:      json.RawMessage("null"),
+		CreatedAt: time.Now(),
+	}
+}
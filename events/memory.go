@@ -0,0 +1,96 @@
+package events
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryBrokerMaxEvents bounds the events kept for replay regardless of their age
+const memoryBrokerMaxEvents = 10000
+
+// memoryBroker only reaches the subscriptions of its own instance
+type memoryBroker struct {
+	*hub
+	mu           sync.Mutex
+	lastSequence uint64
+	recent       []models.Event
+	sequences    []uint64
+	replayWindow time.Duration
+}
+
+func newMemoryBroker(bufferSize int, replayWindow time.Duration) *memoryBroker {
+	return &memoryBroker{
+		hub: newHub(bufferSize),
+		// Sequences keep growing across restarts so IDs from a previous run are never reused
+		lastSequence: uint64(time.Now().UnixNano()),
+		replayWindow: replayWindow,
+	}
+}
+
+func (b *memoryBroker) prune(now time.Time) {
+	i := 0
+	for i < len(b.recent) && (len(b.recent)-i > memoryBrokerMaxEvents || now.Sub(b.recent[i].CreatedAt) > b.replayWindow) {
+		i++
+	}
+
+	b.recent = b.recent[i:]
+	b.sequences = b.sequences[i:]
+}
+
+func (b *memoryBroker) Publish(event models.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastSequence++
+	event.ID = strconv.FormatUint(b.lastSequence, 10)
+
+	b.recent = append(b.recent, event)
+	b.sequences = append(b.sequences, b.lastSequence)
+	b.prune(event.CreatedAt)
+
+	b.dispatch(event)
+
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(userID primitive.ObjectID, lastEventID string) (*Subscription, []models.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription := b.add(userID)
+
+	if lastEventID == "" {
+		return subscription, nil, nil
+	}
+
+	b.prune(time.Now())
+
+	lastSequence, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || lastSequence > b.lastSequence {
+		return subscription, []models.Event{resetEvent(userID)}, nil
+	}
+
+	oldestSequence := b.lastSequence + 1
+	if len(b.sequences) > 0 {
+		oldestSequence = b.sequences[0]
+	}
+
+	// Events between the last one received and the oldest one kept were pruned
+	if lastSequence+1 < oldestSequence {
+		return subscription, []models.Event{resetEvent(userID)}, nil
+	}
+
+	backlog := []models.Event{}
+
+	for i, event := range b.recent {
+		if b.sequences[i] > lastSequence && event.UserID == userID {
+			backlog = append(backlog, event)
+		}
+	}
+
+	return subscription, backlog, nil
+}
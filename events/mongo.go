@@ -0,0 +1,234 @@
+package events
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/db"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoEvent struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	Sequence  uint64             `bson:"sequence"`
+	Type      string             `bson:"type"`
+	Data      string             `bson:"data"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+func (e mongoEvent) event() models.Event {
+	return models.Event{
+		ID:        strconv.FormatUint(e.Sequence, 10),
+		UserID:    e.UserID,
+		Type:      e.Type,
+		Data:      []byte(e.Data),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// mongoBroker shares events between instances through the events collection, every instance
+// watches its change stream and dispatches the inserted events to its own subscriptions. Events are
+// numbered per user, the clocks of the instances are not reliable enough to order them.
+type mongoBroker struct {
+	*hub
+	replayWindow time.Duration
+}
+
+func newMongoBroker(bufferSize int, replayWindow time.Duration) *mongoBroker {
+	b := &mongoBroker{
+		hub:          newHub(bufferSize),
+		replayWindow: replayWindow,
+	}
+
+	go b.watch()
+	go b.purge()
+
+	return b
+}
+
+func (b *mongoBroker) watch() {
+	eventCollection := db.GetCollection(db.DB, "events")
+
+	pipeline := []bson.M{{"$match": bson.M{"operationType": "insert"}}}
+
+	var resumeToken bson.Raw
+
+	for {
+		opts := options.ChangeStream()
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+
+		cs, err := eventCollection.Watch(context.Background(), pipeline, opts)
+		if err != nil {
+			log.Printf("events: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for cs.Next(context.Background()) {
+			change := struct {
+				FullDocument mongoEvent `bson:"fullDocument"`
+			}{}
+
+			if err := cs.Decode(&change); err == nil {
+				b.dispatch(change.FullDocument.event())
+			}
+
+			resumeToken = cs.ResumeToken()
+		}
+
+		if err := cs.Err(); err != nil {
+			log.Printf("events: %v", err)
+		}
+		cs.Close(context.Background())
+
+		time.Sleep(time.Second)
+	}
+}
+
+func (b *mongoBroker) purge() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		eventCollection := db.GetCollection(db.DB, "events")
+
+		filter := bson.M{"createdAt": bson.M{"$lt": time.Now().Add(-b.replayWindow)}}
+
+		if _, err := eventCollection.DeleteMany(ctx, filter); err != nil {
+			log.Printf("events: %v", err)
+		}
+
+		cancel()
+	}
+}
+
+// eventSequence is the last sequence given to the events of a user
+type eventSequence struct {
+	UserID   primitive.ObjectID `bson:"_id"`
+	Sequence uint64             `bson:"sequence"`
+}
+
+func findEventSequence(ctx context.Context, userID primitive.ObjectID) (uint64, error) {
+	eventSequenceCollection := db.GetCollection(db.DB, "eventSequences")
+
+	sequence := eventSequence{}
+
+	err := eventSequenceCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&sequence)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+
+	return sequence.Sequence, nil
+}
+
+func (b *mongoBroker) Publish(event models.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := db.DB.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	eventCollection := db.GetCollection(db.DB, "events")
+	eventSequenceCollection := db.GetCollection(db.DB, "eventSequences")
+
+	// Publishers of the same user conflict on the sequence, so the events are inserted in their order
+	transactionCallback := func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"_id": event.UserID}
+		update := bson.M{"$inc": bson.M{"sequence": 1}}
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+		sequence := eventSequence{}
+
+		if err := eventSequenceCollection.FindOneAndUpdate(sessCtx, filter, update, opts).Decode(&sequence); err != nil {
+			return nil, err
+		}
+
+		document := mongoEvent{
+			ID:        primitive.NewObjectID(),
+			UserID:    event.UserID,
+			Sequence:  sequence.Sequence,
+			Type:      event.Type,
+			Data:      string(event.Data),
+			CreatedAt: event.CreatedAt,
+		}
+
+		return eventCollection.InsertOne(sessCtx, document)
+	}
+
+	maxCommitTime := 10 * time.Second
+	txnOpts := options.Transaction().SetMaxCommitTime(&maxCommitTime)
+
+	_, err = session.WithTransaction(ctx, transactionCallback, txnOpts)
+
+	return err
+}
+
+func (b *mongoBroker) Subscribe(userID primitive.ObjectID, lastEventID string) (*Subscription, []models.Event, error) {
+	subscription := b.add(userID)
+
+	if lastEventID == "" {
+		return subscription, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lastSequence, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return subscription, []models.Event{resetEvent(userID)}, nil
+	}
+
+	sequence, err := findEventSequence(ctx, userID)
+	if err != nil {
+		subscription.Close()
+		return nil, nil, err
+	}
+
+	if lastSequence > sequence {
+		return subscription, []models.Event{resetEvent(userID)}, nil
+	}
+
+	eventCollection := db.GetCollection(db.DB, "events")
+
+	filter := bson.M{"userId": userID, "sequence": bson.M{"$gt": lastSequence}}
+	opts := options.Find().SetSort(bson.M{"sequence": 1})
+
+	documents := []mongoEvent{}
+
+	cur, err := eventCollection.Find(ctx, filter, opts)
+	if err != nil {
+		subscription.Close()
+		return nil, nil, err
+	}
+
+	if err := cur.All(ctx, &documents); err != nil {
+		subscription.Close()
+		return nil, nil, err
+	}
+
+	// Events older than the replay window may have been purged already
+	if sequence > lastSequence && (len(documents) == 0 || documents[0].Sequence != lastSequence+1) {
+		return subscription, []models.Event{resetEvent(userID)}, nil
+	}
+
+	backlog := []models.Event{}
+	for _, document := range documents {
+		backlog = append(backlog, document.event())
+	}
+
+	return subscription, backlog, nil
+}
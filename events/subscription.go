@@ -0,0 +1,94 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Subscription receives the live events of a user. Its buffer is bounded, a client that does not keep
+// up is dropped and is expected to reconnect with the last event ID it received.
+type Subscription struct {
+	userID primitive.ObjectID
+	events chan models.Event
+	done   chan struct{}
+	once   sync.Once
+	hub    *hub
+}
+
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Done is closed when the subscription is closed or dropped for being too slow
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.remove(s)
+		close(s.done)
+	})
+}
+
+func (s *Subscription) send(event models.Event) {
+	select {
+	case s.events <- event:
+	default:
+		go s.Close()
+	}
+}
+
+// hub dispatches the events of a broker to the subscriptions of this instance
+type hub struct {
+	mu            sync.RWMutex
+	subscriptions map[primitive.ObjectID]map[*Subscription]struct{}
+	bufferSize    int
+}
+
+func newHub(bufferSize int) *hub {
+	return &hub{
+		subscriptions: map[primitive.ObjectID]map[*Subscription]struct{}{},
+		bufferSize:    bufferSize,
+	}
+}
+
+func (h *hub) add(userID primitive.ObjectID) *Subscription {
+	subscription := &Subscription{
+		userID: userID,
+		events: make(chan models.Event, h.bufferSize),
+		done:   make(chan struct{}),
+		hub:    h,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscriptions[userID] == nil {
+		h.subscriptions[userID] = map[*Subscription]struct{}{}
+	}
+	h.subscriptions[userID][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (h *hub) remove(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscriptions[subscription.userID], subscription)
+	if len(h.subscriptions[subscription.userID]) == 0 {
+		delete(h.subscriptions, subscription.userID)
+	}
+}
+
+func (h *hub) dispatch(event models.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscription := range h.subscriptions[event.UserID] {
+		subscription.send(event)
+	}
+}
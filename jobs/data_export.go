@@ -0,0 +1,384 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	dataExportStaleAfter  = 30 * time.Minute
+	dataExportMaxAttempts = 3
+)
+
+// dataExportSection is written to the archive both as <name>.json and <name>.csv
+type dataExportSection struct {
+	name   string
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+func dataExportSections(userID primitive.ObjectID) ([]dataExportSection, error) {
+	userResponse, err := crud.FindOneUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	postsResponse, err := crud.FindAllPostsByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	followersResponse, err := crud.FindAllFollowerRelationsByFollowedId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	followingResponse, err := crud.FindAllFollowerRelationsByFollowerId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	likesResponse, err := crud.FindAllLikesByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsResponse, err := crud.FindAllCommentsByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarksResponse, err := crud.FindAllBookmarksByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarkCollectionsResponse, err := crud.FindAllBookmarkCollectionsByUserId(userID, 0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	messagesResponse, err := crud.FindAllMessagesBySenderId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaResponse, err := crud.FindAllMediaByUserId(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	postRevisionsResponse := []models.PostRevisionResponse{}
+	for _, postResponse := range postsResponse {
+		if postResponse.RevisionCount == 0 {
+			continue
+		}
+
+		revisionsResponse, err := crud.FindAllPostRevisionsByPostId(postResponse.ID, 0, int64(postResponse.RevisionCount))
+		if err != nil {
+			return nil, err
+		}
+
+		postRevisionsResponse = append(postRevisionsResponse, revisionsResponse...)
+	}
+
+	profile := dataExportSection{
+		name:  "profile",
+		value: userResponse,
+		header: []string{
+			"id", "fullName", "biography", "location", "birthdate", "gender", "avatarUrl", "coverUrl",
+			"email", "isActive", "isSuperuser", "createdAt", "updatedAt", "followersCount", "followingCount",
+		},
+		rows: [][]string{{
+			userResponse.ID.Hex(),
+			userResponse.FullName,
+			userResponse.Biography,
+			userResponse.Location,
+			formatTime(userResponse.Birthdate),
+			userResponse.Gender,
+			userResponse.AvatarUrl,
+			userResponse.CoverUrl,
+			userResponse.Email,
+			strconv.FormatBool(userResponse.IsActive),
+			strconv.FormatBool(userResponse.IsSuperuser),
+			formatTime(userResponse.CreatedAt),
+			formatTime(userResponse.UpdatedAt),
+			strconv.Itoa(userResponse.FollowersCount),
+			strconv.Itoa(userResponse.FollowingCount),
+		}},
+	}
+
+	posts := dataExportSection{
+		name:   "posts",
+		value:  postsResponse,
+		header: []string{"id", "content", "createdAt", "updatedAt"},
+		rows:   [][]string{},
+	}
+	for _, postResponse := range postsResponse {
+		posts.rows = append(posts.rows, []string{
+			postResponse.ID.Hex(),
+			postResponse.Content,
+			formatTime(postResponse.CreatedAt),
+			formatTime(postResponse.UpdatedAt),
+		})
+	}
+
+	followers := dataExportSection{
+		name:   "followers",
+		value:  followersResponse,
+		header: []string{"id", "followerId", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, followerRelation := range followersResponse {
+		followers.rows = append(followers.rows, []string{
+			followerRelation.ID.Hex(),
+			followerRelation.FollowerID.Hex(),
+			formatTime(followerRelation.CreatedAt),
+		})
+	}
+
+	following := dataExportSection{
+		name:   "following",
+		value:  followingResponse,
+		header: []string{"id", "followedId", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, followerRelation := range followingResponse {
+		following.rows = append(following.rows, []string{
+			followerRelation.ID.Hex(),
+			followerRelation.FollowedID.Hex(),
+			formatTime(followerRelation.CreatedAt),
+		})
+	}
+
+	likes := dataExportSection{
+		name:   "likes",
+		value:  likesResponse,
+		header: []string{"id", "postId", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, likeResponse := range likesResponse {
+		likes.rows = append(likes.rows, []string{
+			likeResponse.ID.Hex(),
+			likeResponse.PostID.Hex(),
+			formatTime(likeResponse.CreatedAt),
+		})
+	}
+
+	comments := dataExportSection{
+		name:   "comments",
+		value:  commentsResponse,
+		header: []string{"id", "postId", "parentId", "content", "createdAt", "updatedAt"},
+		rows:   [][]string{},
+	}
+	for _, commentResponse := range commentsResponse {
+		parentID := ""
+		if commentResponse.ParentID != nil {
+			parentID = commentResponse.ParentID.Hex()
+		}
+
+		comments.rows = append(comments.rows, []string{
+			commentResponse.ID.Hex(),
+			commentResponse.PostID.Hex(),
+			parentID,
+			commentResponse.Content,
+			formatTime(commentResponse.CreatedAt),
+			formatTime(commentResponse.UpdatedAt),
+		})
+	}
+
+	bookmarks := dataExportSection{
+		name:   "bookmarks",
+		value:  bookmarksResponse,
+		header: []string{"id", "postId", "collectionId", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, bookmarkResponse := range bookmarksResponse {
+		collectionID := ""
+		if bookmarkResponse.CollectionID != nil {
+			collectionID = bookmarkResponse.CollectionID.Hex()
+		}
+
+		bookmarks.rows = append(bookmarks.rows, []string{
+			bookmarkResponse.ID.Hex(),
+			bookmarkResponse.PostID.Hex(),
+			collectionID,
+			formatTime(bookmarkResponse.CreatedAt),
+		})
+	}
+
+	bookmarkCollections := dataExportSection{
+		name:   "bookmark_collections",
+		value:  bookmarkCollectionsResponse,
+		header: []string{"id", "name", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, bookmarkCollectionResponse := range bookmarkCollectionsResponse {
+		bookmarkCollections.rows = append(bookmarkCollections.rows, []string{
+			bookmarkCollectionResponse.ID.Hex(),
+			bookmarkCollectionResponse.Name,
+			formatTime(bookmarkCollectionResponse.CreatedAt),
+		})
+	}
+
+	messages := dataExportSection{
+		name:   "messages",
+		value:  messagesResponse,
+		header: []string{"id", "conversationId", "content", "createdAt", "updatedAt"},
+		rows:   [][]string{},
+	}
+	for _, messageResponse := range messagesResponse {
+		messages.rows = append(messages.rows, []string{
+			messageResponse.ID.Hex(),
+			messageResponse.ConversationID.Hex(),
+			messageResponse.Content,
+			formatTime(messageResponse.CreatedAt),
+			formatTime(messageResponse.UpdatedAt),
+		})
+	}
+
+	media := dataExportSection{
+		name:   "media",
+		value:  mediaResponse,
+		header: []string{"id", "postId", "type", "url", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, mediaItemResponse := range mediaResponse {
+		postID := ""
+		if mediaItemResponse.PostID != nil {
+			postID = mediaItemResponse.PostID.Hex()
+		}
+
+		media.rows = append(media.rows, []string{
+			mediaItemResponse.ID.Hex(),
+			postID,
+			mediaItemResponse.Type,
+			mediaItemResponse.Url,
+			formatTime(mediaItemResponse.CreatedAt),
+		})
+	}
+
+	postRevisions := dataExportSection{
+		name:   "post_revisions",
+		value:  postRevisionsResponse,
+		header: []string{"id", "postId", "content", "createdAt"},
+		rows:   [][]string{},
+	}
+	for _, postRevisionResponse := range postRevisionsResponse {
+		postRevisions.rows = append(postRevisions.rows, []string{
+			postRevisionResponse.ID.Hex(),
+			postRevisionResponse.PostID.Hex(),
+			postRevisionResponse.Content,
+			formatTime(postRevisionResponse.CreatedAt),
+		})
+	}
+
+	return []dataExportSection{
+		profile, posts, followers, following, likes, comments, bookmarks, bookmarkCollections, messages, media, postRevisions,
+	}, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func buildDataExport(dataExportID primitive.ObjectID, userID primitive.ObjectID) error {
+	sections, err := dataExportSections(userID)
+	if err != nil {
+		return err
+	}
+
+	archive := utils.NewArchive()
+
+	for _, section := range sections {
+		if err := archive.AddJson(section.name+".json", section.value); err != nil {
+			return err
+		}
+
+		if err := archive.AddCsv(section.name+".csv", section.header, section.rows); err != nil {
+			return err
+		}
+	}
+
+	content, err := archive.Bytes()
+	if err != nil {
+		return err
+	}
+
+	fileName := "export-" + dataExportID.Hex() + ".zip"
+	expiresAt := time.Now().AddDate(0, 0, config.Config.DataExportRetentionDays)
+
+	return crud.CompleteDataExport(dataExportID, fileName, content, expiresAt)
+}
+
+func BuildDataExport(dataExportID primitive.ObjectID, userID primitive.ObjectID) {
+	if err := crud.StartDataExport(dataExportID); err != nil {
+		log.Printf("job build_data_export: %v", err)
+		return
+	}
+
+	completeDataExport(dataExportID, userID)
+}
+
+func completeDataExport(dataExportID primitive.ObjectID, userID primitive.ObjectID) {
+	if err := buildDataExport(dataExportID, userID); err != nil {
+		log.Printf("job build_data_export: %v", err)
+
+		if err := crud.UpdateDataExportStatus(dataExportID, models.DataExportStatusFailed); err != nil {
+			log.Printf("job build_data_export: %v", err)
+		}
+	}
+}
+
+// retryStaleDataExports builds again the data exports whose build was interrupted, giving up after a few attempts
+// so that an export that brings its instance down doesn't do it forever
+func retryStaleDataExports() error {
+	for {
+		dataExportResponse, err := crud.ClaimStaleDataExport(time.Now().Add(-dataExportStaleAfter))
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if dataExportResponse.Attempts > dataExportMaxAttempts {
+			if err := crud.UpdateDataExportStatus(dataExportResponse.ID, models.DataExportStatusFailed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		completeDataExport(dataExportResponse.ID, dataExportResponse.UserID)
+	}
+}
+
+func purgeExpiredDataExports() error {
+	dataExportsResponse, err := crud.FindExpiredDataExports(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, dataExportResponse := range dataExportsResponse {
+		if err := crud.DeleteDataExport(dataExportResponse); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/storage"
+)
+
+func purgeDeletedBlobs() error {
+	deletedBlobs, err := crud.FindDeletedBlobs(1000)
+	if err != nil {
+		return err
+	}
+
+	// A file that fails stays queued and is tried again on the next run
+	for _, deletedBlob := range deletedBlobs {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := storage.DefaultStore.Delete(ctx, deletedBlob.Key)
+		cancel()
+
+		if err == nil {
+			err = crud.DeleteDeletedBlob(deletedBlob.ID)
+		}
+		if err != nil {
+			log.Printf("purge deleted blob %s: %v", deletedBlob.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+func every(interval time.Duration, name string, job func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := job(); err != nil {
+			log.Printf("job %s: %v", name, err)
+		}
+	}
+}
+
+func Start() {
+	go every(time.Hour, "purge_deleted_users", purgeDeletedUsers)
+	go every(time.Hour, "purge_expired_data_exports", purgeExpiredDataExports)
+	go every(10*time.Minute, "retry_stale_data_exports", retryStaleDataExports)
+	go every(time.Hour, "purge_old_notifications", purgeOldNotifications)
+	go every(time.Hour, "purge_deleted_posts", purgeDeletedPosts)
+	go every(time.Hour, "purge_unattached_media", purgeUnattachedMedia)
+	go every(time.Hour, "purge_deleted_blobs", purgeDeletedBlobs)
+	go every(time.Minute, "publish_scheduled_posts", publishScheduledPosts)
+}
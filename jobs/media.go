@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/crud"
+)
+
+// unattachedMediaRetention is how long an upload waits for a post before it is deleted
+const unattachedMediaRetention = 24 * time.Hour
+
+// purgeUnattachedMedia deletes the uploads that were never attached, their files are removed by purgeDeletedBlobs
+func purgeUnattachedMedia() error {
+	media, err := crud.FindMediaToPurge(time.Now().Add(-unattachedMediaRetention))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range media {
+		if err := crud.DeleteMedia(m.ID); err != nil {
+			log.Printf("purge media %s: %v", m.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/crud"
+)
+
+func purgeOldNotifications() error {
+	updatedAt := time.Now().AddDate(0, 0, -config.Config.NotificationRetentionDays)
+
+	return crud.DeleteNotificationsUpdatedBefore(updatedAt)
+}
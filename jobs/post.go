@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/notifications"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func purgeDeletedPosts() error {
+	deletedBefore := time.Now().AddDate(0, 0, -config.Config.PostDeletedRetentionDays)
+
+	return crud.PurgeDeletedPosts(deletedBefore)
+}
+
+// publishScheduledPosts publishes the posts that are due. Publishing is atomic, so each post is claimed by a single
+// instance, and announcements left pending by an instance that stopped are delivered once their lease expires. A post
+// that can't be found once updated is delivered again when its lease expires, if its author can be seen by then.
+func publishScheduledPosts() error {
+	for {
+		postResponse, err := crud.PublishDuePost(time.Now())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if errors.Is(err, crud.ErrUpdatedPostNotFound) {
+			log.Printf("publish scheduled post: %v", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		notifications.AnnouncePost(postResponse)
+	}
+
+	for {
+		postResponse, err := crud.ClaimPendingAnnouncement(time.Now())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if errors.Is(err, crud.ErrUpdatedPostNotFound) {
+			log.Printf("announce pending post: %v", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		notifications.AnnouncePost(postResponse)
+	}
+}
@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/wilfredohq/fiber-start/crud"
+)
+
+func purgeDeletedUsers() error {
+	userIDs, err := crud.FindUserIdsDueForDeletion(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		// One user that can't be deleted must not keep the others around
+		if err := crud.DeleteUser(userID); err != nil {
+			log.Printf("purge deleted user %s: %v", userID.Hex(), err)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/jobs"
 	"github.com/wilfredohq/fiber-start/middleware"
 	"github.com/wilfredohq/fiber-start/routers"
 )
@@ -14,12 +15,15 @@ import (
 // @In header
 // @Name Authorization
 func main() {
-	app := fiber.New()
+	// Bodies over the default limit are streamed to the routes, which check their own limit before reading them
+	app := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
 
 	middleware.FiberMiddleware(app)
 
 	routers.ApiRouter(app)
 
+	jobs.Start()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8000"
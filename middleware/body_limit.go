@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/constants"
+	"github.com/wilfredohq/fiber-start/models"
+)
+
+// BodyLimit rejects the requests with a body larger than the limit. The server streams the bodies over its own limit
+// so the routes that take uploads can raise it, bodies without a length are read up to the limit.
+func BodyLimit(limit int) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		contentLength := c.Request().Header.ContentLength()
+
+		if contentLength < 0 && c.Request().IsBodyStream() {
+			body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+			if err != nil {
+				return c.Status(http.StatusUnprocessableEntity).JSON(models.ValidationError{Detail: err.Error()})
+			}
+
+			contentLength = len(body)
+			c.Request().SetBody(body)
+		}
+
+		if contentLength > limit {
+			// The rest of the body is left unread on the connection
+			c.Response().SetConnectionClose()
+			return c.Status(http.StatusRequestEntityTooLarge).JSON(models.Error{Detail: constants.RequestBodyTooLarge})
+		}
+
+		return c.Next()
+	}
+}
@@ -13,21 +13,51 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func JwtAuth() func(*fiber.Ctx) error {
-	config := jwtware.Config{
+func jwtConfig(tokenLookup string, successHandler fiber.Handler) jwtware.Config {
+	return jwtware.Config{
 		SigningKey:     []byte(config.Config.SecretKey),
 		ContextKey:     "jwt",
 		Claims:         &jwt.RegisteredClaims{},
-		SuccessHandler: jwtSuccess,
+		TokenLookup:    tokenLookup,
+		SuccessHandler: successHandler,
 		ErrorHandler:   jwtError,
 	}
+}
+
+func JwtAuth() func(*fiber.Ctx) error {
+	return jwtware.New(jwtConfig("header:Authorization", jwtSuccess))
+}
 
-	return jwtware.New(config)
+// JwtStreamAuth also accepts an events ticket in the query, browsers cannot set headers on EventSource and
+// WebSocket. Access tokens are never taken from the query, where proxies would log them.
+func JwtStreamAuth() func(*fiber.Ctx) error {
+	return jwtware.New(jwtConfig("header:Authorization,query:ticket", jwtStreamSuccess))
 }
 
 func jwtSuccess(c *fiber.Ctx) error {
 	claims := utils.GetLocalJwtClaims(c)
 
+	// Purpose tokens (email change, ...) carry an audience and must not be used as access tokens
+	if len(claims.Audience) > 0 {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	return setUserID(c, claims)
+}
+
+func jwtStreamSuccess(c *fiber.Ctx) error {
+	claims := utils.GetLocalJwtClaims(c)
+
+	// The header carries access tokens and the query events tickets
+	isTicket := len(claims.Audience) > 0
+	if isTicket != (c.Get(fiber.HeaderAuthorization) == "") || isTicket && !claims.VerifyAudience(utils.EventsAudience, true) {
+		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
+	}
+
+	return setUserID(c, claims)
+}
+
+func setUserID(c *fiber.Ctx, claims *jwt.RegisteredClaims) error {
 	userID, err := primitive.ObjectIDFromHex(claims.Subject)
 	if err != nil {
 		return c.Status(http.StatusUnauthorized).JSON(models.Error{Detail: constants.InvalidJwt})
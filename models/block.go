@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Block struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	BlockerID primitive.ObjectID `bson:"blockerId"`
+	BlockedID primitive.ObjectID `bson:"blockedId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+type BlockResponse struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	BlockerID primitive.ObjectID `bson:"blockerId" json:"blockerId" validate:"required"`
+	BlockedID primitive.ObjectID `bson:"blockedId" json:"blockedId" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Block
+
+type BlockCreate struct {
+	BlockerID primitive.ObjectID `bson:"blockerId"`
+	BlockedID primitive.ObjectID `bson:"blockedId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Bookmark struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `bson:"userId"`
+	PostID       primitive.ObjectID `bson:"postId"`
+	CollectionID primitive.ObjectID `bson:"collectionId,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt"`
+}
+
+type BookmarkResponse struct {
+	ID           primitive.ObjectID  `bson:"_id" json:"id" validate:"required"`
+	UserID       primitive.ObjectID  `bson:"userId" json:"userId" validate:"required"`
+	PostID       primitive.ObjectID  `bson:"postId" json:"postId" validate:"required"`
+	CollectionID *primitive.ObjectID `bson:"collectionId" json:"collectionId"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Bookmark
+
+type BookmarksPage struct {
+	Data       []PostResponse      `json:"data" validate:"required"`
+	NextCursor *primitive.ObjectID `json:"nextCursor"`
+} // @Name BookmarksPage
+
+type BookmarkCreate struct {
+	UserID       primitive.ObjectID  `bson:"userId" json:"-"`
+	PostID       primitive.ObjectID  `bson:"postId" json:"-"`
+	CollectionID *primitive.ObjectID `bson:"collectionId,omitempty" json:"collectionId"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"-"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"-"`
+} // @Name BookmarkCreate
+
+type BookmarkUpdate struct {
+	CollectionID *primitive.ObjectID `bson:"collectionId,omitempty" json:"collectionId"`
+	UpdatedAt    time.Time           `bson:"updatedAt" swaggerignore:"true"`
+} // @Name BookmarkUpdate
+
+type BookmarkCollection struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	Name      string             `bson:"name"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+type BookmarkCollectionResponse struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
+	Name           string             `bson:"name" json:"name" validate:"required"`
+	BookmarksCount int                `bson:"bookmarksCount" json:"bookmarksCount" validate:"required"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name BookmarkCollection
+
+type BookmarkCollectionCreate struct {
+	UserID    primitive.ObjectID `bson:"userId" json:"-"`
+	Name      *string            `bson:"name,omitempty" json:"name" validate:"required,min=1,max=50"`
+	CreatedAt time.Time          `bson:"createdAt" json:"-"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"-"`
+} // @Name BookmarkCollectionCreate
+
+type BookmarkCollectionUpdate struct {
+	Name      *string   `bson:"name,omitempty" json:"name" validate:"omitempty,min=1,max=50"`
+	UpdatedAt time.Time `bson:"updatedAt" swaggerignore:"true"`
+} // @Name BookmarkCollectionUpdate
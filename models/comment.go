@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Comment struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty"`
+	PostID       primitive.ObjectID   `bson:"postId"`
+	UserID       primitive.ObjectID   `bson:"userId"`
+	ParentID     primitive.ObjectID   `bson:"parentId,omitempty"`
+	AncestorIDs  []primitive.ObjectID `bson:"ancestorIds"`
+	Content      string               `bson:"content"`
+	RepliesCount int                  `bson:"repliesCount"`
+	CreatedAt    time.Time            `bson:"createdAt"`
+	UpdatedAt    time.Time            `bson:"updatedAt"`
+}
+
+type CommentResponse struct {
+	ID           primitive.ObjectID   `bson:"_id" json:"id" validate:"required"`
+	PostID       primitive.ObjectID   `bson:"postId" json:"postId" validate:"required"`
+	UserID       primitive.ObjectID   `bson:"userId" json:"userId" validate:"required"`
+	ParentID     *primitive.ObjectID  `bson:"parentId" json:"parentId"`
+	AncestorIDs  []primitive.ObjectID `bson:"ancestorIds" json:"-"`
+	Content      string               `bson:"content" json:"content" validate:"required"`
+	RepliesCount int                  `bson:"repliesCount" json:"repliesCount" validate:"required"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt    time.Time            `bson:"updatedAt" json:"updatedAt" validate:"required"`
+	User         PostUser             `bson:"user" json:"user" validate:"required"`
+} // @Name Comment
+
+type CommentsPage struct {
+	Data       []CommentResponse   `json:"data" validate:"required"`
+	NextCursor *primitive.ObjectID `json:"nextCursor"`
+} // @Name CommentsPage
+
+type CommentCreate struct {
+	PostID      primitive.ObjectID   `bson:"postId" json:"-"`
+	UserID      primitive.ObjectID   `bson:"userId" json:"-"`
+	ParentID    *primitive.ObjectID  `bson:"parentId,omitempty" json:"parentId"`
+	AncestorIDs []primitive.ObjectID `bson:"ancestorIds" json:"-"`
+	Content     *string              `bson:"content,omitempty" json:"content" validate:"required"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"-"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"-"`
+} // @Name CommentCreate
+
+type CommentUpdate struct {
+	Content   *string   `bson:"content,omitempty" json:"content"`
+	UpdatedAt time.Time `bson:"updatedAt" swaggerignore:"true"`
+} // @Name CommentUpdate
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ParticipantStatusPending marks a participant that has not accepted the message request yet
+	ParticipantStatusPending  = "pending"
+	ParticipantStatusAccepted = "accepted"
+)
+
+type ConversationParticipant struct {
+	UserID            primitive.ObjectID  `bson:"userId" json:"userId" validate:"required"`
+	Status            string              `bson:"status" json:"status" validate:"required" enums:"pending,accepted"`
+	LastReadMessageID *primitive.ObjectID `bson:"lastReadMessageId" json:"lastReadMessageId"`
+	LastReadAt        *time.Time          `bson:"lastReadAt" json:"lastReadAt"`
+	UnreadCount       int                 `bson:"unreadCount" json:"-"`
+} // @Name ConversationParticipant
+
+type Conversation struct {
+	ID            primitive.ObjectID        `bson:"_id,omitempty"`
+	CreatorID     primitive.ObjectID        `bson:"creatorId"`
+	IsGroup       bool                      `bson:"isGroup"`
+	Title         string                    `bson:"title"`
+	DirectKey     string                    `bson:"directKey,omitempty"`
+	Participants  []ConversationParticipant `bson:"participants"`
+	LastMessageAt time.Time                 `bson:"lastMessageAt"`
+	CreatedAt     time.Time                 `bson:"createdAt"`
+	UpdatedAt     time.Time                 `bson:"updatedAt"`
+}
+
+type ConversationUser struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FullName  string             `bson:"fullName" json:"fullName" validate:"required"`
+	Handle    string             `bson:"handle" json:"handle" validate:"required"`
+	AvatarUrl string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+} // @Name ConversationUser
+
+type ConversationResponse struct {
+	ID            primitive.ObjectID        `bson:"_id" json:"id" validate:"required"`
+	CreatorID     primitive.ObjectID        `bson:"creatorId" json:"creatorId" validate:"required"`
+	IsGroup       bool                      `bson:"isGroup" json:"isGroup" validate:"required"`
+	Title         string                    `bson:"title" json:"title" validate:"required"`
+	Participants  []ConversationParticipant `bson:"participants" json:"participants" validate:"required"`
+	Users         []ConversationUser        `bson:"users" json:"users" validate:"required"`
+	UnreadCount   int                       `bson:"unreadCount" json:"unreadCount" validate:"required"`
+	LastMessageAt time.Time                 `bson:"lastMessageAt" json:"lastMessageAt" validate:"required"`
+	CreatedAt     time.Time                 `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt     time.Time                 `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Conversation
+
+type ConversationCreate struct {
+	CreatorID      primitive.ObjectID        `bson:"creatorId" json:"-"`
+	IsGroup        bool                      `bson:"isGroup" json:"-"`
+	Title          *string                   `bson:"title,omitempty" json:"title" validate:"omitempty,max=100"`
+	DirectKey      string                    `bson:"directKey,omitempty" json:"-"`
+	ParticipantIDs []primitive.ObjectID      `bson:"-" json:"participantIds" validate:"required,min=1,unique"`
+	Participants   []ConversationParticipant `bson:"participants" json:"-"`
+	LastMessageAt  time.Time                 `bson:"lastMessageAt" json:"-"`
+	CreatedAt      time.Time                 `bson:"createdAt" json:"-"`
+	UpdatedAt      time.Time                 `bson:"updatedAt" json:"-"`
+} // @Name ConversationCreate
+
+type ConversationReceipt struct {
+	ConversationID    primitive.ObjectID  `json:"conversationId" validate:"required"`
+	UserID            primitive.ObjectID  `json:"userId" validate:"required"`
+	LastReadMessageID *primitive.ObjectID `json:"lastReadMessageId"`
+	LastReadAt        *time.Time          `json:"lastReadAt"`
+} // @Name ConversationReceipt
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusCompleted  = "completed"
+	DataExportStatusFailed     = "failed"
+)
+
+type DataExport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      primitive.ObjectID `bson:"userId"`
+	Status      string             `bson:"status"`
+	Attempts    int                `bson:"attempts"`
+	FileID      primitive.ObjectID `bson:"fileId,omitempty"`
+	CompletedAt time.Time          `bson:"completedAt"`
+	ExpiresAt   time.Time          `bson:"expiresAt"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+type DataExportResponse struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
+	Status      string             `bson:"status" json:"status" validate:"required" enums:"pending,processing,completed,failed"`
+	Attempts    int                `bson:"attempts" json:"-"`
+	FileID      primitive.ObjectID `bson:"fileId" json:"-"`
+	CompletedAt time.Time          `bson:"completedAt" json:"completedAt" validate:"required"`
+	ExpiresAt   time.Time          `bson:"expiresAt" json:"expiresAt" validate:"required"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+	DownloadUrl string             `bson:"-" json:"downloadUrl" validate:"required"`
+} // @Name DataExport
+
+type DataExportCreate struct {
+	UserID    primitive.ObjectID `bson:"userId"`
+	Status    string             `bson:"status"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeletedBlob is a file of the blob store that nothing refers to anymore and waits to be removed
+type DeletedBlob struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Key       string             `bson:"key"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
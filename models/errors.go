@@ -1,7 +1,7 @@
 package models
 
 type Error struct {
-	Detail string `json:"detail" validate:"required"  enums:"internal_server_error,endpoint_not_found,invalid_credentials,invalid_jwt,insufficient_privileges,current_user_not_found,current_user_inactive,current_user_not_superuser,user_already_registered,user_not_found,user_inactive,follower_relation_already_registered,follower_relation_not_found,post_not_found"`
+	Detail string `json:"detail" validate:"required"  enums:"internal_server_error,endpoint_not_found,websocket_upgrade_required,request_body_too_large,invalid_credentials,incorrect_password,invalid_jwt,insufficient_privileges,current_user_not_found,current_user_inactive,current_user_not_superuser,user_already_registered,handle_already_registered,user_not_found,user_inactive,user_is_current_user,user_blocked,user_private,block_already_registered,block_not_found,mute_already_registered,mute_not_found,cannot_follow_self,follower_relation_already_registered,follower_relation_not_found,post_not_found,repost_already_registered,repost_not_found,repost_not_editable,post_not_editable,post_not_restorable,post_already_published,post_publish_at_invalid,post_not_shareable,follow_request_not_found,like_already_registered,like_not_found,comment_not_found,media_not_found,media_not_attachable,media_too_large,media_type_not_supported,media_invalid,media_too_long,bookmark_already_registered,bookmark_not_found,bookmark_collection_already_registered,bookmark_collection_not_found,notification_not_found,conversation_not_found,conversation_participants_invalid,message_request_pending,message_not_found,message_not_editable,data_export_not_found,data_export_in_progress,data_export_not_ready,data_export_expired"`
 } // @Name Error
 
 type ValidationError struct {
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	EventTypePostCreated         = "post.created"
+	EventTypeNotificationCreated = "notification.created"
+	EventTypeFollowerCreated     = "follower.created"
+	EventTypeFollowerDeleted     = "follower.deleted"
+	EventTypeConversationCreated = "conversation.created"
+	EventTypeConversationRead    = "conversation.read"
+	EventTypeMessageCreated      = "message.created"
+	EventTypeMessageUpdated      = "message.updated"
+	EventTypeMessageDeleted      = "message.deleted"
+	// EventTypeReset tells the client that events were lost and its state must be fetched again
+	EventTypeReset = "reset"
+)
+
+type Event struct {
+	ID        string             `json:"id" validate:"required"`
+	UserID    primitive.ObjectID `json:"-"`
+	Type      string             `json:"type" validate:"required" enums:"post.created,notification.created,follower.created,follower.deleted,conversation.created,conversation.read,message.created,message.updated,message.deleted,reset"`
+	Data      json.RawMessage    `json:"data" swaggertype:"object"`
+	CreatedAt time.Time          `json:"createdAt" validate:"required"`
+} // @Name Event
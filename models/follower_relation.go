@@ -6,10 +6,16 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	FollowerRelationStatusPending  = "pending"
+	FollowerRelationStatusAccepted = "accepted"
+)
+
 type FollowerRelation struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty"`
 	FollowerID primitive.ObjectID `bson:"followerId"`
 	FollowedID primitive.ObjectID `bson:"followedId"`
+	Status     string             `bson:"status"`
 	CreatedAt  time.Time          `bson:"createdAt"`
 	UpdatedAt  time.Time          `bson:"updatedAt"`
 }
@@ -18,14 +24,36 @@ type FollowerRelationResponse struct {
 	ID         primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
 	FollowerID primitive.ObjectID `bson:"followerId" json:"followerId" validate:"required"`
 	FollowedID primitive.ObjectID `bson:"followedId" json:"followedId" validate:"required"`
+	Status     string             `bson:"status" json:"status" validate:"required" enums:"pending,accepted"`
 	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
 	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
 	HasData    bool               `json:"hasData" validate:"required"`
 } // @Name FollowerRelation
 
 type FollowerRelationCreate struct {
-	FollowerID primitive.ObjectID  `bson:"followerId" swaggerignore:"true"`
+	FollowerID primitive.ObjectID  `bson:"followerId" json:"-"`
 	FollowedID *primitive.ObjectID `bson:"followedId,omitempty" json:"followedId" validate:"required"`
-	CreatedAt  time.Time           `bson:"createdAt" swaggerignore:"true"`
-	UpdatedAt  time.Time           `bson:"updatedAt" swaggerignore:"true"`
+	Status     string              `bson:"status" json:"-"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"-"`
+	UpdatedAt  time.Time           `bson:"updatedAt" json:"-"`
 } // @Name FollowerRelationCreate
+
+type FollowRequestResponse struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FollowerID primitive.ObjectID `bson:"followerId" json:"followerId" validate:"required"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	User       PostUser           `bson:"user" json:"user" validate:"required"`
+} // @Name FollowRequest
+
+type FollowUserResponse struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FullName       string             `bson:"fullName" json:"fullName" validate:"required"`
+	Handle         string             `bson:"handle" json:"handle" validate:"required"`
+	AvatarUrl      string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+	IsPrivate      bool               `bson:"isPrivate" json:"isPrivate" validate:"required"`
+	FollowersCount int                `bson:"followersCount" json:"followersCount" validate:"required"`
+	FollowingCount int                `bson:"followingCount" json:"followingCount" validate:"required"`
+	FollowsYou     bool               `bson:"followsYou" json:"followsYou" validate:"required"`
+	YouFollow      bool               `bson:"youFollow" json:"youFollow" validate:"required"`
+	FollowedAt     time.Time          `bson:"followedAt" json:"followedAt" validate:"required"`
+} // @Name FollowUser
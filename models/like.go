@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Like struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	PostID    primitive.ObjectID `bson:"postId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+type LikeResponse struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
+	PostID    primitive.ObjectID `bson:"postId" json:"postId" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Like
+
+type LikeCreate struct {
+	UserID    primitive.ObjectID `bson:"userId"`
+	PostID    primitive.ObjectID `bson:"postId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
+type Media struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `bson:"userId"`
+	PostID       primitive.ObjectID `bson:"postId,omitempty"`
+	Type         string             `bson:"type"`
+	ContentType  string             `bson:"contentType"`
+	Key          string             `bson:"key"`
+	ThumbnailKey string             `bson:"thumbnailKey"`
+	Url          string             `bson:"url"`
+	ThumbnailUrl string             `bson:"thumbnailUrl"`
+	Width        int                `bson:"width"`
+	Height       int                `bson:"height"`
+	Duration     float64            `bson:"duration"`
+	Size         int                `bson:"size"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt"`
+}
+
+type MediaResponse struct {
+	ID           primitive.ObjectID  `bson:"_id" json:"id" validate:"required"`
+	UserID       primitive.ObjectID  `bson:"userId" json:"userId" validate:"required"`
+	PostID       *primitive.ObjectID `bson:"postId" json:"postId"`
+	Type         string              `bson:"type" json:"type" validate:"required" enums:"image,video"`
+	ContentType  string              `bson:"contentType" json:"contentType" validate:"required"`
+	Url          string              `bson:"url" json:"url" validate:"required"`
+	ThumbnailUrl string              `bson:"thumbnailUrl" json:"thumbnailUrl" validate:"required"`
+	Width        int                 `bson:"width" json:"width" validate:"required"`
+	Height       int                 `bson:"height" json:"height" validate:"required"`
+	Duration     float64             `bson:"duration" json:"duration" validate:"required"`
+	Size         int                 `bson:"size" json:"size" validate:"required"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Media
+
+type MediaCreate struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	UserID       primitive.ObjectID `bson:"userId"`
+	Type         string             `bson:"type"`
+	ContentType  string             `bson:"contentType"`
+	Key          string             `bson:"key"`
+	ThumbnailKey string             `bson:"thumbnailKey"`
+	Url          string             `bson:"url"`
+	ThumbnailUrl string             `bson:"thumbnailUrl"`
+	Width        int                `bson:"width"`
+	Height       int                `bson:"height"`
+	Duration     float64            `bson:"duration"`
+	Size         int                `bson:"size"`
+	CreatedAt    time.Time          `bson:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt"`
+}
+
+// PostAttachment is the copy of a media stored in the post it is attached to
+type PostAttachment struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	Type         string             `bson:"type" json:"type" validate:"required" enums:"image,video"`
+	Url          string             `bson:"url" json:"url" validate:"required"`
+	ThumbnailUrl string             `bson:"thumbnailUrl" json:"thumbnailUrl" validate:"required"`
+	Width        int                `bson:"width" json:"width" validate:"required"`
+	Height       int                `bson:"height" json:"height" validate:"required"`
+	Duration     float64            `bson:"duration" json:"duration" validate:"required"`
+} // @Name PostAttachment
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Message struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ConversationID primitive.ObjectID `bson:"conversationId"`
+	SenderID       primitive.ObjectID `bson:"senderId"`
+	Content        string             `bson:"content"`
+	EditedAt       time.Time          `bson:"editedAt,omitempty"`
+	DeletedAt      time.Time          `bson:"deletedAt,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt"`
+}
+
+type MessageResponse struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	ConversationID primitive.ObjectID `bson:"conversationId" json:"conversationId" validate:"required"`
+	SenderID       primitive.ObjectID `bson:"senderId" json:"senderId" validate:"required"`
+	Content        string             `bson:"content" json:"content" validate:"required"`
+	EditedAt       *time.Time         `bson:"editedAt" json:"editedAt"`
+	DeletedAt      *time.Time         `bson:"deletedAt" json:"deletedAt"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Message
+
+type MessagesPage struct {
+	Data       []MessageResponse   `json:"data" validate:"required"`
+	NextCursor *primitive.ObjectID `json:"nextCursor"`
+} // @Name MessagesPage
+
+type MessageCreate struct {
+	ConversationID primitive.ObjectID `bson:"conversationId" json:"-"`
+	SenderID       primitive.ObjectID `bson:"senderId" json:"-"`
+	Content        *string            `bson:"content,omitempty" json:"content" validate:"required"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"-"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"-"`
+} // @Name MessageCreate
+
+type MessageUpdate struct {
+	Content   *string   `bson:"content,omitempty" json:"content" validate:"required"`
+	EditedAt  time.Time `bson:"editedAt" swaggerignore:"true"`
+	UpdatedAt time.Time `bson:"updatedAt" swaggerignore:"true"`
+} // @Name MessageUpdate
+
+type ConversationRead struct {
+	MessageID *primitive.ObjectID `json:"messageId"`
+} // @Name ConversationRead
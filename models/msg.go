@@ -1,5 +1,5 @@
 package models
 
 type Msg struct {
-	Msg string `json:"msg" validate:"required" enums:"email_sent,password_updated,post_deleted,follower_relation_deleted"`
+	Msg string `json:"msg" validate:"required" enums:"email_sent,password_updated,email_updated,post_deleted,repost_deleted,user_deleted,user_deletion_scheduled,user_unblocked,user_unmuted,follower_relation_deleted,follow_request_rejected,like_deleted,comment_deleted,bookmark_deleted,bookmark_collection_deleted,notifications_read,conversation_left"`
 } // @Name Msg
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Mute struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	MuterID   primitive.ObjectID `bson:"muterId"`
+	MutedID   primitive.ObjectID `bson:"mutedId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
+
+type MuteResponse struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	MuterID   primitive.ObjectID `bson:"muterId" json:"muterId" validate:"required"`
+	MutedID   primitive.ObjectID `bson:"mutedId" json:"mutedId" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Mute
+
+type MuteCreate struct {
+	MuterID   primitive.ObjectID `bson:"muterId"`
+	MutedID   primitive.ObjectID `bson:"mutedId"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+}
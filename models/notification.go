@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NotificationTypeFollowed        = "followed"
+	NotificationTypeFollowRequested = "follow_requested"
+	NotificationTypeFollowAccepted  = "follow_accepted"
+	NotificationTypeLiked           = "liked"
+	NotificationTypeCommented       = "commented"
+	NotificationTypeMentioned       = "mentioned"
+)
+
+// Notification groups similar events, unread notifications of the same type and post collect their
+// actors until the recipient reads them
+type Notification struct {
+	ID        primitive.ObjectID   `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID   `bson:"userId"`
+	Type      string               `bson:"type"`
+	PostID    primitive.ObjectID   `bson:"postId,omitempty"`
+	ActorIDs  []primitive.ObjectID `bson:"actorIds"`
+	Read      bool                 `bson:"read"`
+	CreatedAt time.Time            `bson:"createdAt"`
+	UpdatedAt time.Time            `bson:"updatedAt"`
+}
+
+type NotificationActor struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FullName  string             `bson:"fullName" json:"fullName" validate:"required"`
+	Handle    string             `bson:"handle" json:"handle" validate:"required"`
+	AvatarUrl string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+} // @Name NotificationActor
+
+type NotificationResponse struct {
+	ID          primitive.ObjectID  `bson:"_id" json:"id" validate:"required"`
+	UserID      primitive.ObjectID  `bson:"userId" json:"userId" validate:"required"`
+	Type        string              `bson:"type" json:"type" validate:"required" enums:"followed,follow_requested,follow_accepted,liked,commented,mentioned"`
+	PostID      *primitive.ObjectID `bson:"postId" json:"postId"`
+	Actors      []NotificationActor `bson:"actors" json:"actors" validate:"required"`
+	ActorsCount int                 `bson:"actorsCount" json:"actorsCount" validate:"required"`
+	Read        bool                `bson:"read" json:"read" validate:"required"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt   time.Time           `bson:"updatedAt" json:"updatedAt" validate:"required"`
+} // @Name Notification
+
+type NotificationCreate struct {
+	UserID  primitive.ObjectID
+	Type    string
+	PostID  primitive.ObjectID
+	ActorID primitive.ObjectID
+}
+
+type NotificationsCount struct {
+	Count int `json:"count" validate:"required"`
+} // @Name NotificationsCount
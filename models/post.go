@@ -6,36 +6,116 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+)
+
+// Post is stored with its reposts deleted along with it pointing to it through DeletedWithID. Posts written before
+// statuses existed have none and are published, AnnouncePending is set until the followers and mentioned users of a
+// published post were told about it and AnnounceLeaseUntil is when another instance may tell them again.
 type Post struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	UserID    primitive.ObjectID `bson:"userId"`
-	Content   string             `bson:"content"`
-	CreatedAt time.Time          `bson:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt"`
+	ID                 primitive.ObjectID `bson:"_id,omitempty"`
+	UserID             primitive.ObjectID `bson:"userId"`
+	Content            string             `bson:"content"`
+	CreatedAt          time.Time          `bson:"createdAt"`
+	UpdatedAt          time.Time          `bson:"updatedAt"`
+	LikesCount         int                `bson:"likesCount"`
+	CommentsCount      int                `bson:"commentsCount"`
+	RepostOfID         primitive.ObjectID `bson:"repostOfId,omitempty"`
+	QuoteOfID          primitive.ObjectID `bson:"quoteOfId,omitempty"`
+	RepostsCount       int                `bson:"repostsCount"`
+	Tags               []string           `bson:"tags"`
+	Mentions           []PostMention      `bson:"mentions"`
+	Attachments        []PostAttachment   `bson:"attachments"`
+	EditedAt           time.Time          `bson:"editedAt,omitempty"`
+	RevisionCount      int                `bson:"revisionCount"`
+	DeletedAt          time.Time          `bson:"deletedAt,omitempty"`
+	DeletedBy          primitive.ObjectID `bson:"deletedBy,omitempty"`
+	DeletedWithID      primitive.ObjectID `bson:"deletedWithId,omitempty"`
+	Status             string             `bson:"status,omitempty"`
+	PublishAt          time.Time          `bson:"publishAt,omitempty"`
+	AnnouncePending    bool               `bson:"announcePending,omitempty"`
+	AnnounceLeaseUntil time.Time          `bson:"announceLeaseUntil,omitempty"`
 }
 
+type PostMention struct {
+	UserID primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
+	Handle string             `bson:"handle" json:"handle" validate:"required"`
+	Start  int                `bson:"start" json:"start" validate:"required"`
+	End    int                `bson:"end" json:"end" validate:"required"`
+} // @Name PostMention
+
 type PostUser struct {
 	FullName  string `bson:"fullName" json:"fullName" validate:"required"`
+	Handle    string `bson:"handle" json:"handle" validate:"required"`
 	AvatarUrl string `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+	IsPrivate bool   `bson:"isPrivate" json:"isPrivate" validate:"required"`
 } // @Name PostUser
 
+type PostOriginal struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
+	Content     string             `bson:"content" json:"content" validate:"required"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+	EditedAt    *time.Time         `bson:"editedAt" json:"editedAt"`
+	User        PostUser           `bson:"user" json:"user" validate:"required"`
+	Mentions    []PostMention      `bson:"mentions" json:"mentions" validate:"required"`
+	Attachments []PostAttachment   `bson:"attachments" json:"attachments" validate:"required"`
+} // @Name PostOriginal
+
 type PostResponse struct {
-	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
-	UserID    primitive.ObjectID `bson:"userId" json:"userId" validate:"required"`
-	Content   string             `bson:"content" json:"content" validate:"required"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
-	User      PostUser           `bson:"user" json:"user" validate:"required"`
+	ID             primitive.ObjectID  `bson:"_id" json:"id" validate:"required"`
+	UserID         primitive.ObjectID  `bson:"userId" json:"userId" validate:"required"`
+	Content        string              `bson:"content" json:"content" validate:"required"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt" validate:"required"`
+	EditedAt       *time.Time          `bson:"editedAt" json:"editedAt"`
+	RevisionCount  int                 `bson:"revisionCount" json:"revisionCount" validate:"required"`
+	User           PostUser            `bson:"user" json:"user" validate:"required"`
+	LikesCount     int                 `bson:"likesCount" json:"likesCount" validate:"required"`
+	LikedByMe      bool                `bson:"likedByMe" json:"likedByMe" validate:"required"`
+	BookmarkedByMe bool                `bson:"bookmarkedByMe" json:"bookmarkedByMe" validate:"required"`
+	CommentsCount  int                 `bson:"commentsCount" json:"commentsCount" validate:"required"`
+	RepostOfID     *primitive.ObjectID `bson:"repostOfId" json:"repostOfId"`
+	QuoteOfID      *primitive.ObjectID `bson:"quoteOfId" json:"quoteOfId"`
+	RepostsCount   int                 `bson:"repostsCount" json:"repostsCount" validate:"required"`
+	Original       *PostOriginal       `bson:"original" json:"original"`
+	Tags           []string            `bson:"tags" json:"tags" validate:"required"`
+	Mentions       []PostMention       `bson:"mentions" json:"mentions" validate:"required"`
+	Attachments    []PostAttachment    `bson:"attachments" json:"attachments" validate:"required"`
+	DeletedAt      *time.Time          `bson:"deletedAt" json:"deletedAt"`
+	DeletedBy      *primitive.ObjectID `bson:"deletedBy" json:"deletedBy"`
+	Status         string              `bson:"status" json:"status" validate:"required" enums:"draft,scheduled,published"`
+	PublishAt      *time.Time          `bson:"publishAt" json:"publishAt"`
 } // @Name Post
 
 type PostCreate struct {
-	UserID    primitive.ObjectID `bson:"userId" swaggerignore:"true"`
-	Content   *string            `bson:"content,omitempty" json:"content" validate:"required"`
-	CreatedAt time.Time          `bson:"createdAt" swaggerignore:"true"`
-	UpdatedAt time.Time          `bson:"updatedAt" swaggerignore:"true"`
+	UserID      primitive.ObjectID   `bson:"userId" json:"-"`
+	Content     *string              `bson:"content,omitempty" json:"content" validate:"required"`
+	RepostOfID  *primitive.ObjectID  `bson:"repostOfId,omitempty" json:"-"`
+	QuoteOfID   *primitive.ObjectID  `bson:"quoteOfId,omitempty" json:"quoteOfId"`
+	Tags        []string             `bson:"tags" json:"-"`
+	Mentions    []PostMention        `bson:"mentions" json:"-"`
+	MediaIDs    []primitive.ObjectID `bson:"-" json:"mediaIds" validate:"omitempty,max=4,unique"`
+	Attachments []PostAttachment     `bson:"attachments" json:"-"`
+	Status      *string              `bson:"status,omitempty" json:"status" validate:"omitempty,oneof=draft scheduled published" enums:"draft,scheduled,published"`
+	PublishAt   *time.Time           `bson:"publishAt,omitempty" json:"publishAt"`
+	// Published posts are announced once created
+	AnnouncePending    bool      `bson:"announcePending,omitempty" json:"-"`
+	AnnounceLeaseUntil time.Time `bson:"announceLeaseUntil,omitempty" json:"-"`
+	CreatedAt          time.Time `bson:"createdAt" json:"-"`
+	UpdatedAt          time.Time `bson:"updatedAt" json:"-"`
 } // @Name PostCreate
 
 type PostUpdate struct {
-	Content   *string   `bson:"content,omitempty" json:"content"`
-	UpdatedAt time.Time `bson:"updatedAt" swaggerignore:"true"`
+	Content   *string        `bson:"content,omitempty" json:"content"`
+	Tags      *[]string      `bson:"tags,omitempty" json:"-"`
+	Mentions  *[]PostMention `bson:"mentions,omitempty" json:"-"`
+	EditedAt  *time.Time     `bson:"editedAt,omitempty" json:"-"`
+	Status    *string        `bson:"status,omitempty" json:"status" validate:"omitempty,oneof=draft scheduled" enums:"draft,scheduled"`
+	PublishAt *time.Time     `bson:"publishAt,omitempty" json:"publishAt"`
+	UpdatedAt time.Time      `bson:"updatedAt" swaggerignore:"true"`
 } // @Name PostUpdate
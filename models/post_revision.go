@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PostRevision is a version of a post replaced by an edit, CreatedAt is when it was replaced
+type PostRevision struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	PostID    primitive.ObjectID `bson:"postId"`
+	EditorID  primitive.ObjectID `bson:"editorId"`
+	Content   string             `bson:"content"`
+	Tags      []string           `bson:"tags"`
+	Mentions  []PostMention      `bson:"mentions"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+type PostRevisionResponse struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	PostID    primitive.ObjectID `bson:"postId" json:"postId" validate:"required"`
+	EditorID  primitive.ObjectID `bson:"editorId" json:"editorId" validate:"required"`
+	Content   string             `bson:"content" json:"content" validate:"required"`
+	Tags      []string           `bson:"tags" json:"tags" validate:"required"`
+	Mentions  []PostMention      `bson:"mentions" json:"mentions" validate:"required"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+} // @Name PostRevision
@@ -0,0 +1,7 @@
+package models
+
+type TrendingTag struct {
+	Tag        string  `bson:"tag" json:"tag" validate:"required"`
+	Score      float64 `bson:"score" json:"score" validate:"required"`
+	PostsCount int     `bson:"postsCount" json:"postsCount" validate:"required"`
+} // @Name TrendingTag
@@ -6,69 +6,115 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	MentionPolicyEveryone  = "everyone"
+	MentionPolicyFollowing = "following"
+	MentionPolicyNobody    = "nobody"
+)
+
 type User struct {
-	ID             primitive.ObjectID `bson:"_id"`
-	FullName       string             `bson:"fullName"`
-	Biography      string             `bson:"biography"`
-	Location       string             `bson:"location"`
-	Birthdate      time.Time          `bson:"birthdate"`
-	Gender         string             `bson:"gender"`
-	AvatarUrl      string             `bson:"avatarUrl"`
-	CoverUrl       string             `bson:"coverUrl"`
-	Email          string             `bson:"email"`
-	Password       string             `bson:"password,omitempty"`
-	IsActive       bool               `bson:"isActive"`
-	IsSuperuser    bool               `bson:"isSuperuser"`
-	CreatedAt      time.Time          `bson:"createdAt"`
-	UpdatedAt      time.Time          `bson:"updatedAt"`
-	FollowersCount int                `bson:"followersCount"`
-	FollowingCount int                `bson:"followingCount"`
+	ID                  primitive.ObjectID `bson:"_id"`
+	FullName            string             `bson:"fullName"`
+	Handle              string             `bson:"handle,omitempty"`
+	Biography           string             `bson:"biography"`
+	Location            string             `bson:"location"`
+	Birthdate           time.Time          `bson:"birthdate"`
+	Gender              string             `bson:"gender"`
+	AvatarUrl           string             `bson:"avatarUrl"`
+	AvatarKey           string             `bson:"avatarKey,omitempty"`
+	CoverUrl            string             `bson:"coverUrl"`
+	CoverKey            string             `bson:"coverKey,omitempty"`
+	Email               string             `bson:"email"`
+	Password            string             `bson:"password,omitempty"`
+	IsActive            bool               `bson:"isActive"`
+	IsSuperuser         bool               `bson:"isSuperuser"`
+	IsPrivate           bool               `bson:"isPrivate"`
+	CreatedAt           time.Time          `bson:"createdAt"`
+	UpdatedAt           time.Time          `bson:"updatedAt"`
+	FollowersCount      int                `bson:"followersCount"`
+	FollowingCount      int                `bson:"followingCount"`
+	SessionsRevokedAt   time.Time          `bson:"sessionsRevokedAt"`
+	DeletionScheduledAt *time.Time         `bson:"deletionScheduledAt"`
+	MentionPolicy       string             `bson:"mentionPolicy"`
 }
 
 type UserResponse struct {
-	ID             primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
-	FullName       string             `bson:"fullName" json:"fullName" validate:"required"`
-	Biography      string             `bson:"biography" json:"biography" validate:"required"`
-	Location       string             `bson:"location" json:"location" validate:"required"`
-	Birthdate      time.Time          `bson:"birthdate" json:"birthdate" validate:"required"`
-	Gender         string             `bson:"gender" json:"gender" validate:"required"`
-	AvatarUrl      string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
-	CoverUrl       string             `bson:"coverUrl" json:"coverUrl" validate:"required"`
-	Email          string             `bson:"email" json:"email" validate:"required"`
-	IsActive       bool               `bson:"isActive" json:"isActive" validate:"required"`
-	IsSuperuser    bool               `bson:"isSuperuser" json:"isSuperuser" validate:"required"`
-	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
-	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
-	FollowersCount int                `bson:"followersCount" json:"followersCount" validate:"required"`
-	FollowingCount int                `bson:"followingCount" json:"followingCount" validate:"required"`
+	ID                  primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FullName            string             `bson:"fullName" json:"fullName" validate:"required"`
+	Handle              string             `bson:"handle" json:"handle" validate:"required"`
+	Biography           string             `bson:"biography" json:"biography" validate:"required"`
+	Location            string             `bson:"location" json:"location" validate:"required"`
+	Birthdate           time.Time          `bson:"birthdate" json:"birthdate" validate:"required"`
+	Gender              string             `bson:"gender" json:"gender" validate:"required"`
+	AvatarUrl           string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+	CoverUrl            string             `bson:"coverUrl" json:"coverUrl" validate:"required"`
+	Email               string             `bson:"email" json:"email" validate:"required"`
+	IsActive            bool               `bson:"isActive" json:"isActive" validate:"required"`
+	IsSuperuser         bool               `bson:"isSuperuser" json:"isSuperuser" validate:"required"`
+	IsPrivate           bool               `bson:"isPrivate" json:"isPrivate" validate:"required"`
+	CreatedAt           time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	UpdatedAt           time.Time          `bson:"updatedAt" json:"updatedAt" validate:"required"`
+	FollowersCount      int                `bson:"followersCount" json:"followersCount" validate:"required"`
+	FollowingCount      int                `bson:"followingCount" json:"followingCount" validate:"required"`
+	SessionsRevokedAt   time.Time          `bson:"sessionsRevokedAt" json:"-"`
+	DeletionScheduledAt *time.Time         `bson:"deletionScheduledAt" json:"deletionScheduledAt"`
+	MentionPolicy       string             `bson:"mentionPolicy" json:"mentionPolicy" validate:"required" enums:"everyone,following,nobody"`
 } // @Name User
 
 type UserCreate struct {
-	FullName    *string    `bson:"fullName,omitempty" json:"fullName" validate:"required,min=3"`
-	Biography   *string    `bson:"biography,omitempty" json:"biography"`
-	Location    *string    `bson:"location,omitempty" json:"location"`
-	Birthdate   *time.Time `bson:"birthdate,omitempty" json:"birthdate"`
-	Gender      *string    `bson:"gender,omitempty" json:"gender"`
-	AvatarUrl   *string    `bson:"avatarUrl,omitempty" json:"avatarUrl" validate:"omitempty,url"`
-	CoverUrl    *string    `bson:"coverUrl,omitempty" json:"coverUrl" validate:"omitempty,url"`
-	Email       *string    `bson:"email,omitempty" json:"email" validate:"required,email"`
-	Password    *string    `bson:"password,omitempty" json:"password" validate:"required,min=8"`
-	IsActive    *bool      `bson:"isActive,omitempty" json:"isActive"`
-	IsSuperuser *bool      `bson:"isSuperuser,omitempty" json:"isSuperuser"`
-	CreatedAt   time.Time  `bson:"createdAt" swaggerignore:"true"`
-	UpdatedAt   time.Time  `bson:"updatedAt" swaggerignore:"true"`
+	FullName      *string    `bson:"fullName,omitempty" json:"fullName" validate:"required,min=3"`
+	Handle        *string    `bson:"handle,omitempty" json:"handle" validate:"omitempty,handle"`
+	Biography     *string    `bson:"biography,omitempty" json:"biography"`
+	Location      *string    `bson:"location,omitempty" json:"location"`
+	Birthdate     *time.Time `bson:"birthdate,omitempty" json:"birthdate"`
+	Gender        *string    `bson:"gender,omitempty" json:"gender"`
+	Email         *string    `bson:"email,omitempty" json:"email" validate:"required,email"`
+	Password      *string    `bson:"password,omitempty" json:"password" validate:"required"`
+	IsActive      *bool      `bson:"isActive,omitempty" json:"isActive"`
+	IsSuperuser   *bool      `bson:"isSuperuser,omitempty" json:"isSuperuser"`
+	IsPrivate     *bool      `bson:"isPrivate,omitempty" json:"isPrivate"`
+	MentionPolicy *string    `bson:"mentionPolicy,omitempty" json:"mentionPolicy" validate:"omitempty,oneof=everyone following nobody" enums:"everyone,following,nobody"`
+	CreatedAt     time.Time  `bson:"createdAt" swaggerignore:"true"`
+	UpdatedAt     time.Time  `bson:"updatedAt" swaggerignore:"true"`
 } // @Name UserCreate
 
+// ProfileImageTypes are the images of a profile uploaded through their own endpoints
+const (
+	ProfileImageTypeAvatar = "avatar"
+	ProfileImageTypeCover  = "cover"
+)
+
 type UserUpdate struct {
-	FullName    *string    `bson:"fullName,omitempty" json:"fullName" validate:"omitempty,min=3"`
-	Biography   *string    `bson:"biography,omitempty" json:"biography"`
-	Location    *string    `bson:"location,omitempty" json:"location"`
-	Birthdate   *time.Time `bson:"birthdate,omitempty" json:"birthdate"`
-	Gender      *string    `bson:"gender,omitempty" json:"gender"`
-	AvatarUrl   *string    `bson:"avatarUrl,omitempty" json:"avatarUrl" validate:"omitempty,url"`
-	CoverUrl    *string    `bson:"coverUrl,omitempty" json:"coverUrl" validate:"omitempty,url"`
-	Password    *string    `bson:"password,omitempty" json:"password" validate:"omitempty,min=8"`
-	IsActive    *bool      `bson:"isActive,omitempty" json:"isActive"`
-	IsSuperuser *bool      `bson:"isSuperuser,omitempty" json:"isSuperuser"`
-	UpdatedAt   time.Time  `bson:"updatedAt" swaggerignore:"true"`
+	FullName      *string    `bson:"fullName,omitempty" json:"fullName" validate:"omitempty,min=3"`
+	Handle        *string    `bson:"handle,omitempty" json:"handle" validate:"omitempty,handle"`
+	Biography     *string    `bson:"biography,omitempty" json:"biography"`
+	Location      *string    `bson:"location,omitempty" json:"location"`
+	Birthdate     *time.Time `bson:"birthdate,omitempty" json:"birthdate"`
+	Gender        *string    `bson:"gender,omitempty" json:"gender"`
+	Password      *string    `bson:"-" json:"password"`
+	IsActive      *bool      `bson:"isActive,omitempty" json:"isActive"`
+	IsSuperuser   *bool      `bson:"isSuperuser,omitempty" json:"isSuperuser"`
+	IsPrivate     *bool      `bson:"isPrivate,omitempty" json:"isPrivate"`
+	MentionPolicy *string    `bson:"mentionPolicy,omitempty" json:"mentionPolicy" validate:"omitempty,oneof=everyone following nobody" enums:"everyone,following,nobody"`
+	UpdatedAt     time.Time  `bson:"updatedAt" swaggerignore:"true"`
 } // @Name UserUpdate
+
+const (
+	UserSuggestionReasonFollowedBy      = "followed_by"
+	UserSuggestionReasonSharedFollowers = "shared_followers"
+	UserSuggestionReasonActive          = "active"
+	UserSuggestionReasonPopular         = "popular"
+)
+
+type UserSuggestionResponse struct {
+	ID                   primitive.ObjectID `bson:"_id" json:"id" validate:"required"`
+	FullName             string             `bson:"fullName" json:"fullName" validate:"required"`
+	Handle               string             `bson:"handle" json:"handle" validate:"required"`
+	AvatarUrl            string             `bson:"avatarUrl" json:"avatarUrl" validate:"required"`
+	IsPrivate            bool               `bson:"isPrivate" json:"isPrivate" validate:"required"`
+	FollowersCount       int                `bson:"followersCount" json:"followersCount" validate:"required"`
+	Reason               string             `bson:"reason" json:"reason" validate:"required" enums:"followed_by,shared_followers,active,popular"`
+	FollowedBy           []PostUser         `bson:"followedBy" json:"followedBy" validate:"required"`
+	FollowedByCount      int                `bson:"followedByCount" json:"followedByCount" validate:"required"`
+	SharedFollowersCount int                `bson:"sharedFollowersCount" json:"sharedFollowersCount" validate:"required"`
+} // @Name UserSuggestion
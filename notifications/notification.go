@@ -0,0 +1,25 @@
+package notifications
+
+import (
+	"log"
+
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
+	"github.com/wilfredohq/fiber-start/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notify records a notification and pushes it to the recipient, users are never notified of their own actions
+func Notify(notificationCreate models.NotificationCreate) {
+	if notificationCreate.UserID == notificationCreate.ActorID {
+		return
+	}
+
+	notificationResponse, err := crud.InsertNotification(notificationCreate)
+	if err != nil {
+		log.Printf("notify user %s: %v", notificationCreate.UserID.Hex(), err)
+		return
+	}
+
+	events.Publish([]primitive.ObjectID{notificationResponse.UserID}, models.EventTypeNotificationCreated, notificationResponse)
+}
@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"log"
+
+	"github.com/wilfredohq/fiber-start/crud"
+	"github.com/wilfredohq/fiber-start/events"
+	"github.com/wilfredohq/fiber-start/models"
+	"github.com/wilfredohq/fiber-start/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnnouncePost notifies the users mentioned in a post that was just published and pushes it to the followers of its
+// author. The post is marked announced afterwards, until then the scheduler delivers it again once its lease expires.
+func AnnouncePost(postResponse models.PostResponse) {
+	NotifyMentionedUsers(postResponse, nil)
+	PublishToFollowers(postResponse)
+
+	if err := crud.MarkPostAnnounced(postResponse.ID); err != nil {
+		log.Printf("announce post %s: %v", postResponse.ID.Hex(), err)
+	}
+}
+
+// NotifyMentionedUsers notifies the users mentioned in a post, except the author and those that were
+// already mentioned before an edit
+func NotifyMentionedUsers(postResponse models.PostResponse, previousMentions []models.PostMention) {
+	notifiedUserIDs := map[primitive.ObjectID]bool{postResponse.UserID: true}
+	for _, mention := range previousMentions {
+		notifiedUserIDs[mention.UserID] = true
+	}
+
+	for _, mention := range postResponse.Mentions {
+		if notifiedUserIDs[mention.UserID] {
+			continue
+		}
+		notifiedUserIDs[mention.UserID] = true
+
+		Notify(models.NotificationCreate{
+			UserID:  mention.UserID,
+			Type:    models.NotificationTypeMentioned,
+			PostID:  postResponse.ID,
+			ActorID: postResponse.UserID,
+		})
+
+		userResponse, err := crud.FindOneUserById(mention.UserID)
+		if err != nil {
+			continue
+		}
+
+		utils.SendMentionEmail(userResponse.Email, userResponse.FullName, postResponse.User.FullName, postResponse.ID.Hex())
+	}
+}
+
+// PublishToFollowers pushes a new post to the home feed of the followers of its author
+func PublishToFollowers(postResponse models.PostResponse) {
+	followerRelationsResponse, err := crud.FindAllFollowerRelationsByFollowedId(postResponse.UserID)
+	if err != nil {
+		return
+	}
+
+	followerIDs := []primitive.ObjectID{}
+	for _, followerRelationResponse := range followerRelationsResponse {
+		followerIDs = append(followerIDs, followerRelationResponse.FollowerID)
+	}
+
+	events.Publish(followerIDs, models.EventTypePostCreated, postResponse)
+}
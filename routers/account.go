@@ -2,6 +2,7 @@ package routers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
 	"github.com/wilfredohq/fiber-start/controllers"
 	"github.com/wilfredohq/fiber-start/middleware"
 )
@@ -11,4 +12,21 @@ func accountRouter(router fiber.Router) {
 	router.Post("/login", controllers.Login)
 	router.Post("/recover", controllers.RecoverAccount)
 	router.Post("/reset-password", controllers.ResetPassword)
+	router.Post("/password", middleware.JwtAuth(), controllers.ChangePassword)
+	router.Post("/email", middleware.JwtAuth(), controllers.ChangeEmail)
+	router.Post("/email/confirm", controllers.ConfirmEmailChange)
+	router.Get("/blocks", middleware.JwtAuth(), controllers.GetBlockedUsers)
+	router.Get("/mutes", middleware.JwtAuth(), controllers.GetMutedUsers)
+	router.Get("/bookmarks", middleware.JwtAuth(), controllers.GetBookmarks)
+	router.Get("/bookmark-collections", middleware.JwtAuth(), controllers.GetBookmarkCollections)
+	router.Post("/bookmark-collections", middleware.JwtAuth(), controllers.CreateBookmarkCollection)
+	router.Patch("/bookmark-collections/:bookmarkCollectionId", middleware.JwtAuth(), controllers.UpdateBookmarkCollection)
+	router.Delete("/bookmark-collections/:bookmarkCollectionId", middleware.JwtAuth(), controllers.DeleteBookmarkCollection)
+	router.Post("/export", middleware.JwtAuth(), controllers.CreateDataExport)
+	router.Get("/export/:dataExportId", middleware.JwtAuth(), controllers.GetDataExport)
+	router.Get("/export/:dataExportId/download", controllers.DownloadDataExport)
+	if config.Config.MagicLinkEnabled {
+		router.Post("/magic-link", controllers.RequestMagicLink)
+		router.Post("/magic-link/login", controllers.MagicLinkLogin)
+	}
 }
@@ -2,15 +2,27 @@ package routers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/middleware"
 )
 
 func ApiRouter(app *fiber.App) {
 	swaggerRouter(app.Group("/swagger"))
+	uploadsRouter(app)
 
 	prefix := "/api/v1"
+	// Uploads raise the body limit, the routes registered after the default one keep it
+	mediaRouter(app.Group(prefix + "/media"))
+	profileImageRouter(app.Group(prefix + "/users"))
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit))
+
 	accountRouter(app.Group(prefix + "/account"))
+	commentRouter(app.Group(prefix + "/comments"))
+	conversationRouter(app.Group(prefix + "/conversations"))
+	eventRouter(app.Group(prefix + "/events"))
 	followerRelationRouter(app.Group(prefix + "/follower-relations"))
+	notificationRouter(app.Group(prefix + "/notifications"))
 	postRouter(app.Group(prefix + "/posts"))
+	tagRouter(app.Group(prefix + "/tags"))
 	userRouter(app.Group(prefix + "/users"))
 
 	notFoundRouter(app)
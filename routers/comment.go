@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+)
+
+func commentRouter(router fiber.Router) {
+	router.Get("/:commentId", middleware.JwtAuth(), controllers.GetComment)
+	router.Get("/:commentId/replies", middleware.JwtAuth(), controllers.GetCommentReplies)
+	router.Delete("/:commentId", middleware.JwtAuth(), controllers.DeleteComment)
+	router.Patch("/:commentId", middleware.JwtAuth(), controllers.UpdateComment)
+}
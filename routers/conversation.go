@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+)
+
+func conversationRouter(router fiber.Router) {
+	router.Get("", middleware.JwtAuth(), controllers.GetConversations)
+	router.Post("", middleware.JwtAuth(), controllers.CreateConversation)
+	router.Get("/:conversationId", middleware.JwtAuth(), controllers.GetConversation)
+	router.Post("/:conversationId/accept", middleware.JwtAuth(), controllers.AcceptConversation)
+	router.Post("/:conversationId/leave", middleware.JwtAuth(), controllers.LeaveConversation)
+	router.Post("/:conversationId/read", middleware.JwtAuth(), controllers.ReadConversation)
+	router.Get("/:conversationId/messages", middleware.JwtAuth(), controllers.GetConversationMessages)
+	router.Post("/:conversationId/messages", middleware.JwtAuth(), controllers.CreateMessage)
+	router.Patch("/:conversationId/messages/:messageId", middleware.JwtAuth(), controllers.UpdateMessage)
+	router.Delete("/:conversationId/messages/:messageId", middleware.JwtAuth(), controllers.DeleteMessage)
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+)
+
+func eventRouter(router fiber.Router) {
+	router.Get("", middleware.JwtStreamAuth(), controllers.GetEvents)
+	router.Post("/ticket", middleware.JwtAuth(), controllers.CreateEventsTicket)
+	router.Get("/ws", middleware.JwtStreamAuth(), controllers.UpgradeEventsWebSocket, websocket.New(controllers.EventsWebSocket))
+}
@@ -8,6 +8,9 @@ import (
 
 func followerRelationRouter(router fiber.Router) {
 	router.Post("", middleware.JwtAuth(), controllers.CreateFollowerRelation)
+	router.Get("/requests", middleware.JwtAuth(), controllers.GetFollowRequests)
 	router.Get("/following/:userId", middleware.JwtAuth(), controllers.CheckFollowerRelation)
+	router.Post("/:followerRelationId/accept", middleware.JwtAuth(), controllers.AcceptFollowRequest)
+	router.Post("/:followerRelationId/reject", middleware.JwtAuth(), controllers.RejectFollowRequest)
 	router.Delete("/:followerRelationId", middleware.JwtAuth(), controllers.DeleteFollowerRelation)
 }
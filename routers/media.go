@@ -0,0 +1,37 @@
+package routers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+	"github.com/wilfredohq/fiber-start/storage"
+)
+
+// mediaRouter takes the largest uploads, the size of each media type is checked once its content is known
+func mediaRouter(router fiber.Router) {
+	bodyLimit := config.Config.MediaMaxImageSizeMB
+	if config.Config.MediaMaxVideoSizeMB > bodyLimit {
+		bodyLimit = config.Config.MediaMaxVideoSizeMB
+	}
+
+	router.Post("", middleware.JwtAuth(), middleware.BodyLimit((bodyLimit+1)<<20), controllers.CreateMedia)
+}
+
+// uploadsRouter serves the files of the local store, other stores give URLs of their own. Media is only served with
+// the token of the URLs given to the users that can see it, profile images are public.
+func uploadsRouter(app *fiber.App) {
+	if config.Config.StorageBackend == "local" {
+		mediaPrefix := storage.LocalStoreUrlPrefix + "/media/"
+
+		app.Get(mediaPrefix+":mediaId/:fileName", controllers.GetMediaFile)
+		app.Static(storage.LocalStoreUrlPrefix, config.Config.StorageLocalDir, fiber.Static{
+			MaxAge: 31536000,
+			Next: func(c *fiber.Ctx) bool {
+				return strings.HasPrefix(c.Path(), mediaPrefix)
+			},
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+)
+
+func notificationRouter(router fiber.Router) {
+	router.Get("", middleware.JwtAuth(), controllers.GetNotifications)
+	router.Get("/unread-count", middleware.JwtAuth(), controllers.GetUnreadNotificationsCount)
+	router.Post("/read", middleware.JwtAuth(), controllers.ReadAllNotifications)
+	router.Post("/:notificationId/read", middleware.JwtAuth(), controllers.ReadNotification)
+}
@@ -10,7 +10,22 @@ func postRouter(router fiber.Router) {
 	router.Get("", middleware.JwtAuth(), controllers.GetPosts)
 	router.Post("", middleware.JwtAuth(), controllers.CreatePost)
 	router.Get("/home", middleware.JwtAuth(), controllers.GetHomePosts)
+	router.Get("/deleted", middleware.JwtAuth(), controllers.GetDeletedPosts)
+	router.Get("/drafts", middleware.JwtAuth(), controllers.GetDrafts)
 	router.Get("/:postId", middleware.JwtAuth(), controllers.GetPost)
 	router.Delete("/:postId", middleware.JwtAuth(), controllers.DeletePost)
 	router.Patch("/:postId", middleware.JwtAuth(), controllers.UpdatePost)
+	router.Post("/:postId/restore", middleware.JwtAuth(), controllers.RestorePost)
+	router.Post("/:postId/publish", middleware.JwtAuth(), controllers.PublishPost)
+	router.Get("/:postId/revisions", middleware.JwtAuth(), controllers.GetPostRevisions)
+	router.Get("/:postId/likes", middleware.JwtAuth(), controllers.GetPostLikers)
+	router.Post("/:postId/likes", middleware.JwtAuth(), controllers.CreateLike)
+	router.Delete("/:postId/likes", middleware.JwtAuth(), controllers.DeleteLike)
+	router.Post("/:postId/bookmarks", middleware.JwtAuth(), controllers.CreateBookmark)
+	router.Patch("/:postId/bookmarks", middleware.JwtAuth(), controllers.UpdateBookmark)
+	router.Delete("/:postId/bookmarks", middleware.JwtAuth(), controllers.DeleteBookmark)
+	router.Post("/:postId/reposts", middleware.JwtAuth(), controllers.CreateRepost)
+	router.Delete("/:postId/reposts", middleware.JwtAuth(), controllers.DeleteRepost)
+	router.Get("/:postId/comments", middleware.JwtAuth(), controllers.GetPostComments)
+	router.Post("/:postId/comments", middleware.JwtAuth(), controllers.CreateComment)
 }
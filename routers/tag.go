@@ -0,0 +1,12 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wilfredohq/fiber-start/controllers"
+	"github.com/wilfredohq/fiber-start/middleware"
+)
+
+func tagRouter(router fiber.Router) {
+	router.Get("/trending", middleware.JwtAuth(), controllers.GetTrendingTags)
+	router.Get("/:tag/posts", middleware.JwtAuth(), controllers.GetTagPosts)
+}
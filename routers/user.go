@@ -14,6 +14,27 @@ func userRouter(router fiber.Router) {
 	} else {
 		router.Post("", middleware.JwtAuth(), controllers.CreateUser)
 	}
+	router.Get("/suggestions", middleware.JwtAuth(), controllers.GetUserSuggestions)
 	router.Get("/:userId", middleware.JwtAuth(), controllers.GetUser)
 	router.Patch("/:userId", middleware.JwtAuth(), controllers.UpdateUser)
+	router.Delete("/:userId", middleware.JwtAuth(), controllers.DeleteUser)
+	router.Post("/:userId/restore", middleware.JwtAuth(), controllers.RestoreUser)
+	router.Delete("/:userId/avatar", middleware.JwtAuth(), controllers.DeleteUserAvatar)
+	router.Delete("/:userId/cover", middleware.JwtAuth(), controllers.DeleteUserCover)
+	router.Get("/:userId/followers", middleware.JwtAuth(), controllers.GetFollowers)
+	router.Get("/:userId/following", middleware.JwtAuth(), controllers.GetFollowing)
+	router.Get("/:userId/mutuals", middleware.JwtAuth(), controllers.GetMutuals)
+	router.Post("/:userId/block", middleware.JwtAuth(), controllers.CreateBlock)
+	router.Delete("/:userId/block", middleware.JwtAuth(), controllers.DeleteBlock)
+	router.Post("/:userId/mute", middleware.JwtAuth(), controllers.CreateMute)
+	router.Delete("/:userId/mute", middleware.JwtAuth(), controllers.DeleteMute)
+}
+
+// profileImageRouter takes the uploads of avatars and covers, a little over the size of an image for the rest of the
+// multipart body
+func profileImageRouter(router fiber.Router) {
+	bodyLimit := (config.Config.MediaMaxImageSizeMB + 1) << 20
+
+	router.Put("/:userId/avatar", middleware.JwtAuth(), middleware.BodyLimit(bodyLimit), controllers.UpdateUserAvatar)
+	router.Put("/:userId/cover", middleware.JwtAuth(), middleware.BodyLimit(bodyLimit), controllers.UpdateUserCover)
 }
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LocalStoreUrlPrefix is the path the files of the local store are served from
+const LocalStoreUrlPrefix = "/uploads"
+
+// localStore keeps the files in a directory of the server, every instance must share it
+type localStore struct {
+	dir       string
+	urlPrefix string
+}
+
+func newLocalStore(dir string, urlPrefix string) *localStore {
+	return &localStore{dir: dir, urlPrefix: urlPrefix}
+}
+
+func (s *localStore) path(key string) string {
+	// Keys are generated by the server, cleaning them only guards against leaving the directory
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (s *localStore) Put(ctx context.Context, key string, contentType string, content io.Reader) error {
+	filePath := s.path(key)
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	// Files are written under a temporary name so readers never see them half written
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filePath)
+}
+
+func (s *localStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(s.path(key))
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Empty directories of removed media are left behind otherwise
+	os.Remove(filepath.Dir(s.path(key)))
+
+	return nil
+}
+
+func (s *localStore) URL(key string) string {
+	return s.urlPrefix + "/" + key
+}
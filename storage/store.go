@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/wilfredohq/fiber-start/config"
+)
+
+// BlobStore keeps the uploaded files under keys like "media/<id>/original.jpg". An S3 compatible store only has to
+// implement it and be selected in newStore, URL returns where clients download the file from.
+type BlobStore interface {
+	Put(ctx context.Context, key string, contentType string, content io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+func newStore() BlobStore {
+	switch config.Config.StorageBackend {
+	default:
+		return newLocalStore(config.Config.StorageLocalDir, LocalStoreUrlPrefix)
+	}
+}
+
+var DefaultStore = newStore()
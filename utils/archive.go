@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+)
+
+type Archive struct {
+	buffer *bytes.Buffer
+	writer *zip.Writer
+}
+
+func NewArchive() *Archive {
+	buffer := &bytes.Buffer{}
+
+	return &Archive{buffer: buffer, writer: zip.NewWriter(buffer)}
+}
+
+func (a *Archive) AddJson(name string, value interface{}) error {
+	file, err := a.writer.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func (a *Archive) AddCsv(name string, header []string, rows [][]string) error {
+	file, err := a.writer.Create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
+
+func (a *Archive) Bytes() ([]byte, error) {
+	if err := a.writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return a.buffer.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var hashtagRegexp = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#])#([\p{L}\p{N}_]{1,100})`)
+
+func hasLetter(value string) bool {
+	for _, r := range value {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExtractHashtags returns the distinct lowercase hashtags of a text in order of appearance
+func ExtractHashtags(content string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range hashtagRegexp.FindAllStringSubmatch(content, -1) {
+		tag := strings.ToLower(match[1])
+
+		// Skip things like "#1" that are not meant as topics
+		if !hasLetter(tag) || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/wilfredohq/fiber-start/config"
@@ -21,3 +23,32 @@ func GetJwtClaims(tokenString string) (*jwt.RegisteredClaims, error) {
 
 	return token.Claims.(*jwt.RegisteredClaims), nil
 }
+
+func GetAudienceJwtClaims(tokenString string, audience string) (*jwt.RegisteredClaims, error) {
+	claims, err := GetJwtClaims(tokenString)
+	if err != nil {
+		return claims, err
+	}
+
+	if !claims.VerifyAudience(audience, true) {
+		return &jwt.RegisteredClaims{}, errors.New("invalid audience")
+	}
+
+	return claims, nil
+}
+
+func GetEmailChangeJwtClaims(tokenString string) (*EmailChangeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailChangeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.Config.SecretKey), nil
+	})
+	if err != nil {
+		return &EmailChangeClaims{}, err
+	}
+
+	claims := token.Claims.(*EmailChangeClaims)
+	if !claims.VerifyAudience(EmailChangeAudience, true) {
+		return &EmailChangeClaims{}, errors.New("invalid audience")
+	}
+
+	return claims, nil
+}
@@ -59,3 +59,48 @@ func SendResetPasswordEmail(emailTo string) {
 
 	sendEmail(emailTo, 6, params)
 }
+
+func SendEmailChangeConfirmationEmail(userID string, email string, newEmail string) {
+	tokenString, err := GetEmailChangeJwt(userID, email, newEmail, config.Config.EmailChangeTokenExpirationMinutes)
+	if err != nil {
+		return
+	}
+
+	params := map[string]interface{}{
+		"projectName":  config.Config.ProjectName,
+		"validMinutes": config.Config.EmailChangeTokenExpirationMinutes,
+		"link":         fmt.Sprintf("%s/confirmar-correo?token=%s", config.Config.ClientUrl, tokenString),
+	}
+
+	sendEmail(newEmail, 7, params)
+}
+
+func SendEmailChangeNoticeEmail(emailTo string, newEmail string) {
+	params := map[string]interface{}{
+		"projectName": config.Config.ProjectName,
+		"newEmail":    newEmail,
+	}
+
+	sendEmail(emailTo, 8, params)
+}
+
+func SendMagicLinkEmail(emailTo string, token string) {
+	params := map[string]interface{}{
+		"projectName":  config.Config.ProjectName,
+		"validMinutes": config.Config.MagicLinkExpirationMinutes,
+		"link":         fmt.Sprintf("%s/acceder?token=%s", config.Config.ClientUrl, token),
+	}
+
+	sendEmail(emailTo, 9, params)
+}
+
+func SendMentionEmail(emailTo string, fullName string, authorFullName string, postID string) {
+	params := map[string]interface{}{
+		"projectName":    config.Config.ProjectName,
+		"fullName":       fullName,
+		"authorFullName": authorFullName,
+		"link":           fmt.Sprintf("%s/publicaciones/%s", config.Config.ClientUrl, postID),
+	}
+
+	sendEmail(emailTo, 10, params)
+}
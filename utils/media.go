@@ -0,0 +1,578 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/wilfredohq/fiber-start/config"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+var (
+	ErrMediaTypeNotSupported = errors.New("media type not supported")
+	ErrMediaInvalid          = errors.New("media invalid")
+	ErrMediaTooLong          = errors.New("media too long")
+)
+
+const (
+	// mediaMaxImagePixels rejects images that are small files but huge once decoded
+	mediaMaxImagePixels = 40_000_000
+	// mediaMaxGifPixels caps the frames of a GIF image by the size of its canvas, each frame is kept once decoded
+	mediaMaxGifPixels  = 200_000_000
+	mediaThumbnailSize = 480
+	mediaJpegQuality   = 90
+)
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+	"video/mp4":  "mp4",
+	"video/webm": "webm",
+}
+
+// ProcessedMedia is an upload ready to be stored, without metadata and with a JPEG thumbnail
+type ProcessedMedia struct {
+	ContentType string
+	Extension   string
+	Content     []byte
+	Thumbnail   []byte
+	Width       int
+	Height      int
+	Duration    float64
+}
+
+// SniffMediaType detects the type of an upload from its content, the name and headers sent by the client are ignored
+func SniffMediaType(content []byte) string {
+	return http.DetectContentType(content)
+}
+
+func IsImageMediaType(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+
+	return false
+}
+
+func IsVideoMediaType(contentType string) bool {
+	switch contentType {
+	case "video/mp4", "video/webm":
+		return true
+	}
+
+	return false
+}
+
+// decodeImage decodes an image upright. The frames of a GIF image are returned along with its first one drawn on the
+// canvas.
+func decodeImage(content []byte, contentType string) (image.Image, *gif.GIF, error) {
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if contentType == "image/webp" {
+		imageConfig, err = webp.DecodeConfig(bytes.NewReader(content))
+	}
+	if err != nil || imageConfig.Width*imageConfig.Height > mediaMaxImagePixels {
+		return nil, nil, ErrMediaInvalid
+	}
+
+	var img image.Image
+	var animation *gif.GIF
+
+	switch contentType {
+	case "image/jpeg":
+		if img, err = jpeg.Decode(bytes.NewReader(content)); err != nil {
+			return nil, nil, ErrMediaInvalid
+		}
+
+		img = orientImage(img, jpegOrientation(content))
+	case "image/png":
+		if img, err = png.Decode(bytes.NewReader(content)); err != nil {
+			return nil, nil, ErrMediaInvalid
+		}
+	case "image/gif":
+		// The frames are counted before any of them is decoded
+		framesCount, err := gifFramesCount(content)
+		if err != nil || framesCount == 0 || framesCount*imageConfig.Width*imageConfig.Height > mediaMaxGifPixels {
+			return nil, nil, ErrMediaInvalid
+		}
+
+		if animation, err = gif.DecodeAll(bytes.NewReader(content)); err != nil || len(animation.Image) == 0 {
+			return nil, nil, ErrMediaInvalid
+		}
+
+		// The first frame may only cover part of the canvas
+		canvas := image.NewRGBA(image.Rect(0, 0, animation.Config.Width, animation.Config.Height))
+		draw.Draw(canvas, animation.Image[0].Bounds(), animation.Image[0], animation.Image[0].Bounds().Min, draw.Over)
+		img = canvas
+	case "image/webp":
+		if img, err = webp.Decode(bytes.NewReader(content)); err != nil {
+			return nil, nil, ErrMediaInvalid
+		}
+	default:
+		return nil, nil, ErrMediaTypeNotSupported
+	}
+
+	return img, animation, nil
+}
+
+// gifFramesCount walks the blocks of a GIF image without decompressing them and counts its frames
+func gifFramesCount(content []byte) (int, error) {
+	if len(content) < 13 || (string(content[:6]) != "GIF87a" && string(content[:6]) != "GIF89a") {
+		return 0, ErrMediaInvalid
+	}
+
+	pos := 13
+	// Global color table
+	if content[10]&0x80 != 0 {
+		pos += 3 << (content[10]&0x07 + 1)
+	}
+
+	// skipSubBlocks returns the position after a sequence of data sub-blocks, the last one being empty
+	skipSubBlocks := func(pos int) (int, error) {
+		for {
+			if pos >= len(content) {
+				return 0, ErrMediaInvalid
+			}
+			if content[pos] == 0 {
+				return pos + 1, nil
+			}
+			pos += 1 + int(content[pos])
+		}
+	}
+
+	framesCount := 0
+
+	for {
+		if pos >= len(content) {
+			return 0, ErrMediaInvalid
+		}
+
+		var err error
+
+		switch content[pos] {
+		case 0x21:
+			// Extension, its label then its sub-blocks
+			if pos, err = skipSubBlocks(pos + 2); err != nil {
+				return 0, err
+			}
+		case 0x2C:
+			// Image descriptor, an optional local color table, the LZW code size then the image data
+			if pos+10 > len(content) {
+				return 0, ErrMediaInvalid
+			}
+			flags := content[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			if pos, err = skipSubBlocks(pos + 1); err != nil {
+				return 0, err
+			}
+			framesCount++
+		case 0x3B:
+			return framesCount, nil
+		default:
+			return 0, ErrMediaInvalid
+		}
+	}
+}
+
+// ProcessImage strips the metadata of an image, EXIF included, and generates its thumbnail. JPEG and PNG images are
+// re-encoded, applying the EXIF orientation first, GIF images keep their frames and WebP images lose their metadata
+// chunks.
+func ProcessImage(content []byte, contentType string) (ProcessedMedia, error) {
+	if !IsImageMediaType(contentType) {
+		return ProcessedMedia{}, ErrMediaTypeNotSupported
+	}
+
+	img, animation, err := decodeImage(content, contentType)
+	if err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	processedMedia := ProcessedMedia{ContentType: contentType, Extension: mediaExtensions[contentType]}
+
+	output := bytes.Buffer{}
+
+	switch contentType {
+	case "image/jpeg":
+		if err := jpeg.Encode(&output, img, &jpeg.Options{Quality: mediaJpegQuality}); err != nil {
+			return ProcessedMedia{}, err
+		}
+	case "image/png":
+		if err := png.Encode(&output, img); err != nil {
+			return ProcessedMedia{}, err
+		}
+	case "image/gif":
+		if err := gif.EncodeAll(&output, animation); err != nil {
+			return ProcessedMedia{}, err
+		}
+	case "image/webp":
+		stripped, err := stripWebpMetadata(content)
+		if err != nil {
+			return ProcessedMedia{}, err
+		}
+		output.Write(stripped)
+	}
+
+	processedMedia.Content = output.Bytes()
+	processedMedia.Width = img.Bounds().Dx()
+	processedMedia.Height = img.Bounds().Dy()
+
+	if processedMedia.Thumbnail, err = encodeThumbnail(img); err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	return processedMedia, nil
+}
+
+// ProcessProfileImage crops an image around its center to the aspect of the given size and resizes it to that size,
+// the result is a JPEG image without metadata
+func ProcessProfileImage(content []byte, contentType string, width int, height int) (ProcessedMedia, error) {
+	if !IsImageMediaType(contentType) {
+		return ProcessedMedia{}, ErrMediaTypeNotSupported
+	}
+
+	img, _, err := decodeImage(content, contentType)
+	if err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	bounds := img.Bounds()
+	crop := bounds
+
+	// The larger side is cut evenly on both ends
+	if bounds.Dx()*height > bounds.Dy()*width {
+		cropWidth := bounds.Dy() * width / height
+		if cropWidth < 1 {
+			cropWidth = 1
+		}
+		crop.Min.X += (bounds.Dx() - cropWidth) / 2
+		crop.Max.X = crop.Min.X + cropWidth
+	} else {
+		cropHeight := bounds.Dx() * height / width
+		if cropHeight < 1 {
+			cropHeight = 1
+		}
+		crop.Min.Y += (bounds.Dy() - cropHeight) / 2
+		crop.Max.Y = crop.Min.Y + cropHeight
+	}
+
+	resized, err := encodeResizedJpeg(img, crop, width, height)
+	if err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	return ProcessedMedia{
+		ContentType: "image/jpeg",
+		Extension:   mediaExtensions["image/jpeg"],
+		Content:     resized,
+		Width:       width,
+		Height:      height,
+	}, nil
+}
+
+func encodeThumbnail(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > mediaThumbnailSize || height > mediaThumbnailSize {
+		if width >= height {
+			width, height = mediaThumbnailSize, height*mediaThumbnailSize/width
+		} else {
+			width, height = width*mediaThumbnailSize/height, mediaThumbnailSize
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	return encodeResizedJpeg(img, bounds, width, height)
+}
+
+// encodeResizedJpeg scales the given part of an image to the given size and encodes it as a JPEG image
+func encodeResizedJpeg(img image.Image, part image.Rectangle, width int, height int) ([]byte, error) {
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	// Transparent pixels turn black in JPEG otherwise
+	draw.Draw(resized, resized.Bounds(), image.White, image.Point{}, draw.Src)
+	xdraw.CatmullRom.Scale(resized, resized.Bounds(), img, part, xdraw.Over, nil)
+
+	output := bytes.Buffer{}
+
+	if err := jpeg.Encode(&output, resized, &jpeg.Options{Quality: mediaJpegQuality}); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// jpegOrientation reads the orientation tag of the EXIF data of a JPEG image, 1 when it is missing
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(content) && content[pos] == 0xFF; {
+		marker := content[pos+1]
+		segmentLength := int(binary.BigEndian.Uint16(content[pos+2:]))
+		if segmentLength < 2 || pos+2+segmentLength > len(content) {
+			break
+		}
+		segment := content[pos+4 : pos+2+segmentLength]
+
+		// The image data starts after SOS, metadata segments come before it
+		if marker == 0xDA {
+			break
+		}
+
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+
+		pos += 2 + segmentLength
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var byteOrder binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		byteOrder = binary.LittleEndian
+	case "MM":
+		byteOrder = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(byteOrder.Uint32(tiff[4:]))
+	if ifdOffset < 8 || ifdOffset+2 > len(tiff) {
+		return 1
+	}
+
+	entriesCount := int(byteOrder.Uint16(tiff[ifdOffset:]))
+	for i := 0; i < entriesCount; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		if byteOrder.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(byteOrder.Uint16(tiff[entry+8:]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			break
+		}
+	}
+
+	return 1
+}
+
+// orientImage turns an image as described by its EXIF orientation so it displays upright without the tag
+func orientImage(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dstX, dstY int
+
+			switch orientation {
+			case 2:
+				dstX, dstY = width-1-x, y
+			case 3:
+				dstX, dstY = width-1-x, height-1-y
+			case 4:
+				dstX, dstY = x, height-1-y
+			case 5:
+				dstX, dstY = y, x
+			case 6:
+				dstX, dstY = height-1-y, x
+			case 7:
+				dstX, dstY = height-1-y, width-1-x
+			case 8:
+				dstX, dstY = y, width-1-x
+			}
+
+			dst.Set(dstX, dstY, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
+
+// stripWebpMetadata drops the EXIF and XMP chunks of a WebP image and their flags in the VP8X header
+func stripWebpMetadata(content []byte) ([]byte, error) {
+	if len(content) < 12 || string(content[:4]) != "RIFF" || string(content[8:12]) != "WEBP" {
+		return nil, ErrMediaInvalid
+	}
+
+	output := append([]byte{}, content[:12]...)
+
+	for pos := 12; pos < len(content); {
+		if pos+8 > len(content) {
+			return nil, ErrMediaInvalid
+		}
+
+		fourCC := string(content[pos : pos+4])
+		chunkEnd := pos + 8 + int(binary.LittleEndian.Uint32(content[pos+4:]))
+		if chunkEnd > len(content) {
+			return nil, ErrMediaInvalid
+		}
+
+		// Chunks are padded to an even size
+		paddedEnd := chunkEnd + chunkEnd%2
+		if paddedEnd > len(content) {
+			paddedEnd = len(content)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte{}, content[pos:paddedEnd]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04
+			}
+			output = append(output, chunk...)
+		default:
+			output = append(output, content[pos:paddedEnd]...)
+		}
+
+		pos = paddedEnd
+	}
+
+	binary.LittleEndian.PutUint32(output[4:], uint32(len(output)-8))
+
+	return output, nil
+}
+
+// ProcessVideo strips the metadata of a video with ffmpeg, checks its duration and takes its first frame as
+// thumbnail. Videos are not supported when ffmpeg is not configured.
+func ProcessVideo(ctx context.Context, content []byte, contentType string) (ProcessedMedia, error) {
+	if !IsVideoMediaType(contentType) || config.Config.FfmpegPath == "" || config.Config.FfprobePath == "" {
+		return ProcessedMedia{}, ErrMediaTypeNotSupported
+	}
+
+	dir, err := os.MkdirTemp("", "media-*")
+	if err != nil {
+		return ProcessedMedia{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	extension := mediaExtensions[contentType]
+	inputPath := filepath.Join(dir, "input."+extension)
+	outputPath := filepath.Join(dir, "output."+extension)
+	thumbnailPath := filepath.Join(dir, "thumbnail.jpg")
+
+	if err := os.WriteFile(inputPath, content, 0o600); err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	probeOutput, err := exec.CommandContext(ctx, config.Config.FfprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		inputPath,
+	).Output()
+	if err != nil {
+		return ProcessedMedia{}, ErrMediaInvalid
+	}
+
+	probe := struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}{}
+
+	if err := json.Unmarshal(probeOutput, &probe); err != nil || len(probe.Streams) == 0 {
+		return ProcessedMedia{}, ErrMediaInvalid
+	}
+
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return ProcessedMedia{}, ErrMediaInvalid
+	}
+
+	if duration > float64(config.Config.MediaMaxVideoSeconds) {
+		return ProcessedMedia{}, ErrMediaTooLong
+	}
+
+	stripArgs := []string{"-v", "error", "-y", "-i", inputPath, "-map", "0", "-map_metadata", "-1", "-c", "copy"}
+	if contentType == "video/mp4" {
+		stripArgs = append(stripArgs, "-movflags", "+faststart")
+	}
+	stripArgs = append(stripArgs, outputPath)
+
+	if err := exec.CommandContext(ctx, config.Config.FfmpegPath, stripArgs...).Run(); err != nil {
+		return ProcessedMedia{}, ErrMediaInvalid
+	}
+
+	if err := exec.CommandContext(ctx, config.Config.FfmpegPath,
+		"-v", "error", "-y",
+		"-i", inputPath,
+		"-frames:v", "1",
+		"-vf", "scale="+strconv.Itoa(mediaThumbnailSize)+":"+strconv.Itoa(mediaThumbnailSize)+":force_original_aspect_ratio=decrease",
+		thumbnailPath,
+	).Run(); err != nil {
+		return ProcessedMedia{}, ErrMediaInvalid
+	}
+
+	processedMedia := ProcessedMedia{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       probe.Streams[0].Width,
+		Height:      probe.Streams[0].Height,
+		Duration:    duration,
+	}
+
+	if processedMedia.Content, err = os.ReadFile(outputPath); err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	if processedMedia.Thumbnail, err = os.ReadFile(thumbnailPath); err != nil {
+		return ProcessedMedia{}, err
+	}
+
+	return processedMedia, nil
+}
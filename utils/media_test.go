@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+// tiffWithOrientation builds the TIFF header of an EXIF segment with a single orientation entry
+func tiffWithOrientation(byteOrder binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12)
+
+	if byteOrder == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	byteOrder.PutUint16(tiff[2:], 42)
+	byteOrder.PutUint32(tiff[4:], 8)
+	byteOrder.PutUint16(tiff[8:], 1)
+	byteOrder.PutUint16(tiff[10:], 0x0112)
+	byteOrder.PutUint16(tiff[12:], 3)
+	byteOrder.PutUint32(tiff[14:], 1)
+	byteOrder.PutUint16(tiff[18:], orientation)
+
+	return tiff
+}
+
+// jpegWithSegments builds the start of a JPEG image with the given segments, followed by the start of its image data
+func jpegWithSegments(segments ...[]byte) []byte {
+	content := []byte{0xFF, 0xD8}
+
+	for _, segment := range segments {
+		content = append(content, segment...)
+	}
+
+	return append(content, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func jpegSegment(marker byte, data []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(data)+2))
+
+	return append(segment, data...)
+}
+
+func exifSegment(tiff []byte) []byte {
+	return jpegSegment(0xE1, append([]byte("Exif\x00\x00"), tiff...))
+}
+
+func TestTiffOrientation(t *testing.T) {
+	tiff := tiffWithOrientation(binary.BigEndian, 6)
+
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{"big endian", tiff, 6},
+		{"little endian", tiffWithOrientation(binary.LittleEndian, 3), 3},
+		{"empty", nil, 1},
+		{"truncated header", tiff[:5], 1},
+		{"truncated entries", tiff[:12], 1},
+		{"truncated entry", tiff[:len(tiff)-1], 1},
+		{"unknown byte order", append([]byte("XX"), tiff[2:]...), 1},
+		{"offset out of range", append(append([]byte{}, tiff[:4]...), 0xFF, 0xFF, 0xFF, 0xFF), 1},
+		{"offset inside header", append(append([]byte{}, tiff[:4]...), 0, 0, 0, 2), 1},
+		{"invalid orientation", tiffWithOrientation(binary.BigEndian, 9), 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := tiffOrientation(test.tiff); got != test.want {
+				t.Errorf("tiffOrientation() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJpegOrientation(t *testing.T) {
+	content := jpegWithSegments(jpegSegment(0xE0, []byte("JFIF\x00")), exifSegment(tiffWithOrientation(binary.BigEndian, 8)))
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    int
+	}{
+		{"exif after jfif", content, 8},
+		{"without exif", jpegWithSegments(jpegSegment(0xE0, []byte("JFIF\x00"))), 1},
+		{"exif after image data", append(jpegWithSegments(), exifSegment(tiffWithOrientation(binary.BigEndian, 8))...), 1},
+		{"empty", nil, 1},
+		{"not a jpeg", []byte("GIF89a"), 1},
+		{"only the start marker", content[:2], 1},
+		{"truncated segment", content[:len(content)-20], 1},
+		{"segment length too short", jpegWithSegments([]byte{0xFF, 0xE1, 0x00, 0x01}), 1},
+		{"segment length past the end", jpegWithSegments([]byte{0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x'}), 1},
+		{"exif too short", jpegWithSegments(jpegSegment(0xE1, []byte("Exif\x00\x00II"))), 1},
+		{"missing segment marker", append([]byte{0xFF, 0xD8, 0x00}, content[2:]...), 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := jpegOrientation(test.content); got != test.want {
+				t.Errorf("jpegOrientation() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+// webpWithChunks builds a WebP image out of the given chunks
+func webpWithChunks(chunks ...[]byte) []byte {
+	content := []byte("RIFF\x00\x00\x00\x00WEBP")
+
+	for _, chunk := range chunks {
+		content = append(content, chunk...)
+	}
+	binary.LittleEndian.PutUint32(content[4:], uint32(len(content)-8))
+
+	return content
+}
+
+func webpChunk(fourCC string, data []byte) []byte {
+	chunk := append([]byte(fourCC), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(data)))
+	chunk = append(chunk, data...)
+
+	// Chunks are padded to an even size
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+
+	return chunk
+}
+
+func TestStripWebpMetadata(t *testing.T) {
+	vp8x := webpChunk("VP8X", []byte{0x08 | 0x04 | 0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	strippedVp8x := webpChunk("VP8X", []byte{0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	vp8l := webpChunk("VP8L", []byte{0x2F, 0x01, 0x02})
+	exif := webpChunk("EXIF", []byte("Exif\x00\x00MM"))
+	xmp := webpChunk("XMP ", []byte("<x:xmpmeta/>"))
+
+	content := webpWithChunks(vp8x, vp8l, exif, xmp)
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"exif and xmp", content, webpWithChunks(strippedVp8x, vp8l), false},
+		{"without metadata", webpWithChunks(vp8l), webpWithChunks(vp8l), false},
+		{"unpadded last chunk", webpWithChunks(vp8l[:len(vp8l)-1]), webpWithChunks(vp8l[:len(vp8l)-1]), false},
+		{"empty", nil, nil, true},
+		{"not riff", append([]byte("RIFX"), content[4:]...), nil, true},
+		{"not webp", append(append([]byte{}, content[:8]...), "WAVE"...), nil, true},
+		{"truncated header", content[:10], nil, true},
+		{"truncated chunk header", content[:len(content)-len(xmp)+4], nil, true},
+		{"truncated chunk", content[:len(content)-2], nil, true},
+		{"chunk size past the end", webpWithChunks([]byte("VP8L\xFF\xFF\xFF\x7F")), nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := stripWebpMetadata(test.content)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("stripWebpMetadata() error = %v, want error %v", err, test.wantErr)
+			}
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("stripWebpMetadata() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGifFramesCount(t *testing.T) {
+	animation := &gif.GIF{}
+	palette := color.Palette{color.Black, color.White}
+	for i := 0; i < 3; i++ {
+		animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), palette))
+		animation.Delay = append(animation.Delay, 10)
+	}
+
+	output := bytes.Buffer{}
+	if err := gif.EncodeAll(&output, animation); err != nil {
+		t.Fatal(err)
+	}
+	content := output.Bytes()
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    int
+		wantErr bool
+	}{
+		{"three frames", content, 3, false},
+		{"empty", nil, 0, true},
+		{"not a gif", append([]byte("GIF90a"), content[6:]...), 0, true},
+		{"truncated header", content[:10], 0, true},
+		{"truncated color table", content[:14], 0, true},
+		{"missing trailer", content[:len(content)-1], 0, true},
+		{"truncated frame", content[:len(content)-4], 0, true},
+		{"unknown block", append(append([]byte{}, content[:len(content)-1]...), 0x99), 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := gifFramesCount(test.content)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("gifFramesCount() error = %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("gifFramesCount() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
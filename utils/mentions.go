@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var handleRegexp = regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`)
+
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])(@[A-Za-z0-9_]{3,30})\b`)
+
+type Mention struct {
+	Handle string
+	// Start and End are offsets in Unicode code points, End is exclusive and both include the @
+	Start int
+	End   int
+}
+
+func IsValidHandle(handle string) bool {
+	return handleRegexp.MatchString(handle)
+}
+
+// ExtractMentions returns every @handle of a text with its position, handles are lowercased
+func ExtractMentions(content string) []Mention {
+	mentions := []Mention{}
+
+	for _, match := range mentionRegexp.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+
+		mentions = append(mentions, Mention{
+			Handle: strings.ToLower(content[start+1 : end]),
+			Start:  utf8.RuneCountInString(content[:start]),
+			End:    utf8.RuneCountInString(content[:end]),
+		})
+	}
+
+	return mentions
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hashes are stored in PHC string format, the prefix identifies the algorithm:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key> or a legacy bcrypt $2a$/$2b$ hash.
+const (
+	argon2idPrefix    = "$argon2id$"
+	argon2idSaltLen   = 16
+	argon2idKeyLen    = 32
+	passwordHashParts = 6
+)
+
+var ErrInvalidPasswordHash = errors.New("invalid password hash")
+
+var bcryptPrefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func currentArgon2idParams() argon2idParams {
+	return argon2idParams{
+		memory:      uint32(config.Config.PasswordHashMemory),
+		iterations:  uint32(config.Config.PasswordHashIterations),
+		parallelism: uint8(config.Config.PasswordHashParallelism),
+	}
+}
+
+func decodeArgon2idHash(hashedPassword string) (argon2idParams, []byte, []byte, error) {
+	params := argon2idParams{}
+
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != passwordHashParts {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	version := 0
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	return params, salt, key, nil
+}
+
+func isBcryptHash(hashedPassword string) bool {
+	for _, prefix := range bcryptPrefixes {
+		if strings.HasPrefix(hashedPassword, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func VerifyPassword(plainPassword string, hashedPassword string) error {
+	if isBcryptHash(hashedPassword) {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
+	}
+
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return ErrInvalidPasswordHash
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(plainPassword), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return bcrypt.ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+func GetPasswordHash(password string) (string, error) {
+	params := currentArgon2idParams()
+
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, argon2idKeyLen)
+
+	hashedPassword := fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.memory,
+		params.iterations,
+		params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return hashedPassword, nil
+}
+
+// PasswordNeedsRehash reports whether a hash was made with another algorithm or outdated parameters
+func PasswordNeedsRehash(hashedPassword string) bool {
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return true
+	}
+
+	params, _, _, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params != currentArgon2idParams()
+}
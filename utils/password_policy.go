@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/wilfredohq/fiber-start/config"
+	"github.com/wilfredohq/fiber-start/constants"
+)
+
+//go:embed data/breached_passwords.txt
+var embeddedBreachedPasswords string
+
+var breachedPasswordHashes = func() map[string]bool {
+	hashes := map[string]bool{}
+
+	for _, password := range strings.Fields(embeddedBreachedPasswords) {
+		hashes[getSha1Hash(password)] = true
+	}
+
+	return hashes
+}()
+
+func getSha1Hash(value string) string {
+	hash := sha1.Sum([]byte(value))
+
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// isBreachedPassword looks the password up in the embedded list and, when configured, in a local
+// directory of k-anonymity range files named after the first 5 SHA-1 hex characters and holding
+// SUFFIX:COUNT lines, the format served by the Pwned Passwords range API
+func isBreachedPassword(password string) (bool, error) {
+	hash := getSha1Hash(password)
+
+	if breachedPasswordHashes[hash] {
+		return true, nil
+	}
+
+	if config.Config.BreachedPasswordsDir == "" {
+		return false, nil
+	}
+
+	file, err := os.Open(filepath.Join(config.Config.BreachedPasswordsDir, hash[:5]))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+
+	suffix := hash[5:]
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.EqualFold(strings.SplitN(line, ":", 2)[0], suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+func countCharacterClasses(password string) int {
+	hasLower, hasUpper, hasDigit, hasSymbol := false, false, false, false
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	count := 0
+	for _, has := range []bool{hasLower, hasUpper, hasDigit, hasSymbol} {
+		if has {
+			count++
+		}
+	}
+
+	return count
+}
+
+// personalInfoParts splits emails and names into the pieces a password should not contain
+func personalInfoParts(personalInfo []string) []string {
+	parts := []string{}
+
+	for _, info := range personalInfo {
+		info = strings.ToLower(strings.SplitN(info, "@", 2)[0])
+
+		for _, part := range strings.FieldsFunc(info, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(part)) >= 3 {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	return parts
+}
+
+func PasswordPolicyViolations(password string, personalInfo ...string) ([]string, error) {
+	violations := []string{}
+
+	if len([]rune(password)) < config.Config.PasswordMinLength {
+		violations = append(violations, constants.PasswordTooShort)
+	}
+
+	if countCharacterClasses(password) < config.Config.PasswordMinCharacterClasses {
+		violations = append(violations, constants.PasswordTooSimple)
+	}
+
+	lowerPassword := strings.ToLower(password)
+	for _, part := range personalInfoParts(personalInfo) {
+		if strings.Contains(lowerPassword, part) {
+			violations = append(violations, constants.PasswordContainsPersonalInfo)
+			break
+		}
+	}
+
+	breached, err := isBreachedPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	if breached {
+		violations = append(violations, constants.PasswordBreached)
+	}
+
+	return violations, nil
+}
+
+// PasswordPolicyErrors returns the violations keyed by field, like ValidatorErrors, or nil when the password is accepted
+func PasswordPolicyErrors(field string, password string, personalInfo ...string) (map[string]interface{}, error) {
+	violations, err := PasswordPolicyViolations(password, personalInfo...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(violations) == 0 {
+		return nil, nil
+	}
+
+	return map[string]interface{}{field: violations}, nil
+}
@@ -1,17 +1,65 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/wilfredohq/fiber-start/config"
-	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	EmailChangeAudience = "email_change"
+	DataExportAudience  = "data_export"
+	EventsAudience      = "events"
+	MediaAudience       = "media"
+)
+
+type EmailChangeClaims struct {
+	Email    string `json:"email"`
+	NewEmail string `json:"newEmail"`
+	jwt.RegisteredClaims
+}
+
+func init() {
+	// Revoked sessions are told apart by the issue time of their tokens, seconds are too coarse for that
+	jwt.TimePrecision = time.Millisecond
+}
+
 func GetJwt(subject string, expirationMinutes int) (string, error) {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(time.Minute * time.Duration(expirationMinutes))
+
+	claims := jwt.RegisteredClaims{
+		Subject:   subject,
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(config.Config.SecretKey))
+	if err != nil {
+		return tokenString, err
+	}
+
+	return tokenString, nil
+}
+
+func GetEmailChangeJwt(userID string, email string, newEmail string, expirationMinutes int) (string, error) {
 	expiresAt := time.Now().Add(time.Minute * time.Duration(expirationMinutes))
 
-	claims := jwt.RegisteredClaims{Subject: subject, ExpiresAt: jwt.NewNumericDate(expiresAt)}
+	claims := EmailChangeClaims{
+		Email:    email,
+		NewEmail: newEmail,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{EmailChangeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -23,19 +71,58 @@ func GetJwt(subject string, expirationMinutes int) (string, error) {
 	return tokenString, nil
 }
 
-func VerifyPassword(plainPassword string, hashedPassword string) error {
-	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword)); err != nil {
-		return err
+func GetAudienceJwt(subject string, audience string, expirationMinutes int) (string, error) {
+	expiresAt := time.Now().Add(time.Minute * time.Duration(expirationMinutes))
+
+	claims := jwt.RegisteredClaims{
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(config.Config.SecretKey))
+	if err != nil {
+		return tokenString, err
 	}
 
-	return nil
+	return tokenString, nil
 }
 
-func GetPasswordHash(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// GetEventsTicketJwt returns a short-lived token to open an event stream where headers can't be set. It keeps the
+// issue time of the session it was asked from, so revoking that session also ends the stream.
+func GetEventsTicketJwt(subject string, sessionIssuedAt time.Time, expirationMinutes int) (string, error) {
+	expiresAt := time.Now().Add(time.Minute * time.Duration(expirationMinutes))
+
+	claims := jwt.RegisteredClaims{
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{EventsAudience},
+		IssuedAt:  jwt.NewNumericDate(sessionIssuedAt),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(config.Config.SecretKey))
 	if err != nil {
+		return tokenString, err
+	}
+
+	return tokenString, nil
+}
+
+func GetRandomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
 
-	return string(bytes), nil
+	return hex.EncodeToString(bytes), nil
+}
+
+func GetTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
 }
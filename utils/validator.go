@@ -18,6 +18,10 @@ func NewValidator() *validator.Validate {
 		return name
 	})
 
+	validate.RegisterValidation("handle", func(fl validator.FieldLevel) bool {
+		return IsValidHandle(fl.Field().String())
+	})
+
 	return validate
 }
 